@@ -2,14 +2,24 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime/pprof"
+	"sort"
+	"strings"
 	"syscall"
+	"text/template"
+	"time"
 
 	"github.com/nodelike/diffloc/internal/analyzer"
+	"github.com/nodelike/diffloc/internal/ci"
+	"github.com/nodelike/diffloc/internal/coverage"
+	"github.com/nodelike/diffloc/internal/model"
+	"github.com/nodelike/diffloc/internal/policy"
+	"github.com/nodelike/diffloc/internal/report"
 	"github.com/nodelike/diffloc/internal/ui"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -27,6 +37,65 @@ var (
 	staticOutput   bool
 	jsonOutput     bool
 	maxDepth       int
+	goSymbols      bool
+	ignoreGofmt    bool
+	complexity     bool
+	groupBy        string
+	perModule      bool
+	affectedOnly   bool
+	outputFormat   string
+	summaryRow     bool
+	topFiles       int
+	outputFile     string
+	templateFile   string
+	templateString string
+	ciProvider     string
+	warnLines      int
+	warnAdditions  int
+	strict         bool
+	hunks          bool
+	coverProfiles  []string
+	coverageStrip  string
+
+	// userTemplate is the parsed --template or --template-string
+	userTemplate *template.Template
+
+	badgeMetric     string
+	badgeLanguage   string
+	badgeLabel      string
+	badgeColor      string
+	badgeLabelColor string
+	badgeThresholds string
+
+	maxAdditions    int
+	maxDeletions    int
+	maxChangedFiles int
+	maxNetChange    int
+	minTestRatio    int
+	checkFormat     string
+
+	compareFormat string
+	compareDepth  int
+)
+
+// exitViolations is the exit code of diffloc check when a budget is exceeded
+const exitViolations = 2
+
+// Output formats accepted by --format
+const (
+	formatTUI      = "tui"
+	formatStatic   = "static"
+	formatJSON     = "json"
+	formatCSV      = "csv"
+	formatTSV      = "tsv"
+	formatMarkdown = "markdown"
+	formatHTML     = "html"
+	formatTemplate = "template"
+	formatMetrics  = "openmetrics"
+	formatNDJSON   = "ndjson"
+	formatSARIF    = "sarif"
+	formatHunks    = "hunks"
+	formatQuickfix = "quickfix"
 )
 
 var rootCmd = &cobra.Command{
@@ -46,6 +115,57 @@ showing changes and statistics.`,
 	Run:  runAnalyze,
 }
 
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the --json report format",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		os.Stdout.Write(report.JSONSchema)
+	},
+}
+
+var viewCmd = &cobra.Command{
+	Use:   "view <report.json>",
+	Short: "Browse a saved JSON report in the TUI",
+	Long: `Load a report written with --json and browse it in the interactive TUI,
+with the same sorting and drill-downs as a live analysis, without checking out
+or analyzing anything. Use "-" to read the report from stdin, which prints the
+static view.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runView,
+}
+
+var compareCmd = &cobra.Command{
+	Use:   "compare <old.json> <new.json>",
+	Short: "Compare two saved JSON reports",
+	Long: `Compare two reports written with --json, typically of consecutive releases,
+and show how the codebase grew: files added and deleted between them, and the
+change in lines per file, directory and language. Either report may be read
+from stdin with "-".`,
+	Args: cobra.ExactArgs(2),
+	Run:  runCompare,
+}
+
+var badgeCmd = &cobra.Command{
+	Use:   "badge [path]",
+	Short: "Write an SVG badge for lines of code or diff size",
+	Long: `Write a shields.io-style SVG badge, generated fully offline, showing the
+total lines of code, the lines of one language or the current diff size.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runBadge,
+}
+
+var checkCmd = &cobra.Command{
+	Use:   "check [path]",
+	Short: "Fail when the current diff exceeds the size budgets",
+	Long: `Analyze the current diff and enforce the size budgets from the budgets
+section of .diffloc.yaml or the --max-* flags. Exits with status 2 and lists
+every violation when a budget is exceeded or a policy rule is broken.
+--format sarif writes the violations as a SARIF 2.1.0 log instead.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runCheck,
+}
+
 func init() {
 	cobra.OnInitialize(initConfig)
 
@@ -54,26 +174,97 @@ func init() {
 	// Add all flags to both root and analyze commands so they work either way
 	addAnalyzeFlags := func(cmd *cobra.Command) {
 		cmd.Flags().BoolVar(&noGitignore, "no-gitignore", false, "Ignore .gitignore patterns (always-excluded patterns still apply)")
-		cmd.Flags().BoolVar(&excludeTests, "exclude-tests", false, "Exclude test files (_test.go, test/, tests/, *.test.*, *.spec.*)")
+		cmd.Flags().BoolVar(&excludeTests, "exclude-tests", false, "Exclude test files (_test.go, test_*.py, *.test.*, *.spec.*, test/, tests/; see tests in the config)")
 		cmd.Flags().StringArrayVar(&customExcludes, "exclude", []string{}, "Additional exclusion pattern (can be repeated)")
 		cmd.Flags().StringArrayVar(&allowedExts, "ext", []string{}, "Override allowed file extensions (can be repeated)")
 		cmd.Flags().StringVar(&cpuProfile, "profile-cpu", "", "Write CPU profile to file")
 		cmd.Flags().StringVar(&memProfile, "profile-mem", "", "Write memory profile to file")
-		cmd.Flags().BoolVar(&staticOutput, "static", false, "Print static output without interactive UI")
-		cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
+		cmd.Flags().BoolVar(&staticOutput, "static", false, "Print static output without interactive UI (same as --format static)")
+		cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON (same as --format json)")
+		cmd.Flags().StringVar(&outputFormat, "format", "", "Output format: tui, static, json, csv, tsv, markdown, html, openmetrics, ndjson, sarif, hunks or quickfix")
+		cmd.Flags().BoolVar(&summaryRow, "summary-row", false, "Append a totals row to csv/tsv output")
+		cmd.Flags().IntVar(&topFiles, "top", 10, "Number of largest changed files listed in markdown output")
+		cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Write the report to a file instead of stdout (not for tui/static)")
+		cmd.Flags().StringVar(&templateFile, "template", "", "Render output with a Go text/template file")
+		cmd.Flags().StringVar(&templateString, "template-string", "", "Render output with an inline Go text/template")
+		cmd.Flags().StringVar(&ciProvider, "ci", "", "CI integration: github (default when GITHUB_ACTIONS=true) or none")
+		cmd.Flags().IntVar(&warnLines, "warn-lines", 1000, "CI: annotate changed files longer than this many lines (0 = off)")
+		cmd.Flags().IntVar(&warnAdditions, "warn-additions", 500, "CI: annotate files adding more than this many lines (0 = off)")
 		cmd.Flags().IntVar(&maxDepth, "max-depth", 0, "Maximum directory traversal depth (0 = unlimited)")
+		cmd.Flags().BoolVar(&strict, "strict", false, "Exit with status 2 when the change breaks the budgets or policy rules in the config")
+		cmd.Flags().BoolVar(&goSymbols, "go-symbols", false, "Report Go functions, types and methods touched by changed .go files")
+		cmd.Flags().BoolVar(&ignoreGofmt, "ignore-gofmt", false, "Normalize .go files with gofmt before diffing so formatting-only changes count as zero")
+		cmd.Flags().BoolVar(&complexity, "complexity", false, "Compute complexity metrics (cyclomatic for Go, indentation-based otherwise)")
+		cmd.Flags().StringVar(&groupBy, "group-by", "", "Aggregate files by dir[:N], ext, lang, module or owner")
+		cmd.Flags().BoolVar(&perModule, "per-module", false, "Report subtotals per detected module (go.mod, go.work, package.json workspaces, pyproject.toml)")
+		cmd.Flags().BoolVar(&affectedOnly, "affected-modules", false, "Only analyze modules touched by the current diff")
+		cmd.Flags().BoolVar(&hunks, "hunks", false, "Include the changed line ranges of each file in JSON output")
+		cmd.Flags().StringArrayVar(&coverProfiles, "coverprofile", []string{}, "Report test coverage of the changed lines from a Go profile, LCOV or Cobertura XML report (can be repeated)")
+		cmd.Flags().StringVar(&coverageStrip, "coverage-strip-prefix", "", "Path prefix removed from the file names of coverage reports")
 
 		viper.BindPFlag("no-gitignore", cmd.Flags().Lookup("no-gitignore"))
 		viper.BindPFlag("exclude-tests", cmd.Flags().Lookup("exclude-tests"))
 		viper.BindPFlag("exclude", cmd.Flags().Lookup("exclude"))
 		viper.BindPFlag("ext", cmd.Flags().Lookup("ext"))
 		viper.BindPFlag("max-depth", cmd.Flags().Lookup("max-depth"))
+		viper.BindPFlag("go-symbols", cmd.Flags().Lookup("go-symbols"))
+		viper.BindPFlag("ignore-gofmt", cmd.Flags().Lookup("ignore-gofmt"))
+		viper.BindPFlag("complexity", cmd.Flags().Lookup("complexity"))
+		viper.BindPFlag("group-by", cmd.Flags().Lookup("group-by"))
+		viper.BindPFlag("format", cmd.Flags().Lookup("format"))
+		viper.BindPFlag("per-module", cmd.Flags().Lookup("per-module"))
+		viper.BindPFlag("affected-modules", cmd.Flags().Lookup("affected-modules"))
+		viper.BindPFlag("coverage-strip-prefix", cmd.Flags().Lookup("coverage-strip-prefix"))
+		viper.BindPFlag("strict", cmd.Flags().Lookup("strict"))
+		viper.BindPFlag("ci", cmd.Flags().Lookup("ci"))
+		viper.BindPFlag("warn-lines", cmd.Flags().Lookup("warn-lines"))
+		viper.BindPFlag("warn-additions", cmd.Flags().Lookup("warn-additions"))
 	}
 
 	addAnalyzeFlags(analyzeCmd)
 	addAnalyzeFlags(rootCmd)
 
+	// Subcommands share the file selection flags; their defaults come from the
+	// config file through the bindings above
+	addFilterFlags := func(cmd *cobra.Command) {
+		cmd.Flags().BoolVar(&noGitignore, "no-gitignore", false, "Ignore .gitignore patterns (always-excluded patterns still apply)")
+		cmd.Flags().BoolVar(&excludeTests, "exclude-tests", false, "Exclude test files (_test.go, test_*.py, *.test.*, *.spec.*, test/, tests/; see tests in the config)")
+		cmd.Flags().StringArrayVar(&customExcludes, "exclude", []string{}, "Additional exclusion pattern (can be repeated)")
+		cmd.Flags().StringArrayVar(&allowedExts, "ext", []string{}, "Override allowed file extensions (can be repeated)")
+		cmd.Flags().IntVar(&maxDepth, "max-depth", 0, "Maximum directory traversal depth (0 = unlimited)")
+	}
+
+	addFilterFlags(badgeCmd)
+	badgeCmd.Flags().StringVar(&badgeMetric, "metric", report.BadgeLines, "Badge metric: lines, lang or diff")
+	badgeCmd.Flags().StringVar(&badgeLanguage, "lang", "", "Language counted by --metric lang (e.g. Go)")
+	badgeCmd.Flags().StringVar(&badgeLabel, "label", "", "Badge label (default depends on the metric)")
+	badgeCmd.Flags().StringVar(&badgeColor, "color", "", "Fixed badge color, a shields.io name or hex value (overrides --thresholds)")
+	badgeCmd.Flags().StringVar(&badgeLabelColor, "label-color", "grey", "Label background color")
+	badgeCmd.Flags().StringVar(&badgeThresholds, "thresholds", "", `Color by value, e.g. "200:brightgreen,500:yellow,red" (diff default)`)
+	badgeCmd.Flags().StringVarP(&outputFile, "output", "o", "", "Write the badge to a file instead of stdout")
+
 	rootCmd.AddCommand(analyzeCmd)
+	rootCmd.AddCommand(schemaCmd)
+	addFilterFlags(checkCmd)
+	checkCmd.Flags().IntVar(&maxAdditions, "max-additions", 0, "Maximum lines added (overrides budgets.max-additions)")
+	checkCmd.Flags().IntVar(&maxDeletions, "max-deletions", 0, "Maximum lines removed (overrides budgets.max-deletions)")
+	checkCmd.Flags().IntVar(&maxChangedFiles, "max-changed-files", 0, "Maximum changed files (overrides budgets.max-changed-files)")
+	checkCmd.Flags().IntVar(&maxNetChange, "max-net-change", 0, "Maximum lines added minus removed (overrides budgets.max-net-change)")
+	checkCmd.Flags().IntVar(&minTestRatio, "min-test-ratio", 0, "Minimum test lines added per 100 production lines added (overrides budgets.min-test-ratio)")
+	checkCmd.Flags().StringVar(&checkFormat, "format", "text", "Output format: text or sarif")
+	checkCmd.Flags().StringVarP(&outputFile, "output", "o", "", "Write the sarif report to a file instead of stdout")
+
+	viewCmd.Flags().BoolVar(&staticOutput, "static", false, "Print static output without interactive UI")
+
+	compareCmd.Flags().StringVar(&compareFormat, "format", formatTUI, "Output format: tui, static, json or markdown")
+	compareCmd.Flags().IntVar(&compareDepth, "depth", 1, "Number of path components directories are rolled up to")
+	compareCmd.Flags().IntVar(&topFiles, "top", 10, "Number of largest file changes listed in markdown output")
+	compareCmd.Flags().StringVarP(&outputFile, "output", "o", "", "Write the comparison to a file instead of stdout (not for tui/static)")
+
+	rootCmd.AddCommand(badgeCmd)
+	rootCmd.AddCommand(checkCmd)
+	rootCmd.AddCommand(viewCmd)
+	rootCmd.AddCommand(compareCmd)
 
 	rootCmd.Run = analyzeCmd.Run
 }
@@ -95,28 +286,34 @@ func initConfig() {
 }
 
 func runAnalyze(cmd *cobra.Command, args []string) {
-	if len(args) > 0 {
-		path = args[0]
-	} else {
-		path = "."
+	format, err := resolveFormat()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
 	}
 
-	if path == "." {
-		var err error
-		path, err = os.Getwd()
+	if format == formatHunks || format == formatQuickfix {
+		hunks = true
+	}
+
+	if format == formatTemplate {
+		userTemplate, err = loadTemplate()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to get current directory: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 	}
 
-	if err := analyzer.ValidatePath(path); err != nil {
+	if ciProvider == "" {
+		ciProvider = viper.GetString("ci")
+	}
+	provider, err := ci.Detect(ciProvider)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
-
-	if shouldWarn, warning := analyzer.ShouldWarnLargeDirectory(path); shouldWarn {
-		fmt.Fprintln(os.Stderr, warning)
+	if provider != "" && format == formatTUI {
+		format = formatStatic
 	}
 
 	if cpuProfile != "" {
@@ -133,6 +330,346 @@ func runAnalyze(cmd *cobra.Command, args []string) {
 		defer pprof.StopCPUProfile()
 	}
 
+	switch format {
+	case formatNDJSON, formatSARIF, formatHunks, formatQuickfix:
+		if groupBy != "" || perModule {
+			fmt.Fprintf(os.Stderr, "Error: --group-by and --per-module are not supported with --format %s\n", format)
+			os.Exit(1)
+		}
+	}
+
+	var stats *model.Stats
+	var grouping analyzer.GroupBy
+	if format == formatNDJSON {
+		stats, grouping = runStream(cmd, args, provider)
+	} else {
+		stats, grouping = analyzePath(cmd, args, nil)
+		if err := writeOutput(format, stats, grouping); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if provider == ci.ProviderGitHub {
+		if err := runGitHub(cmd, format, stats, grouping); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if !cmd.Flags().Changed("strict") {
+		strict = viper.GetBool("strict")
+	}
+	if strict && len(stats.Violations) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: %d policy violation(s) with --strict\n", len(stats.Violations))
+		os.Exit(exitViolations)
+	}
+
+	if memProfile != "" {
+		f, err := os.Create(memProfile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to create memory profile: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		if err := pprof.WriteHeapProfile(f); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to write memory profile: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// runStream writes files as NDJSON while they are counted, so memory stays
+// flat and the first record is available immediately. For the GitHub step
+// summary and annotations, changed files are kept and every file is counted
+// towards the directory rollup on the way out.
+func runStream(cmd *cobra.Command, args []string, provider string) (*model.Stats, analyzer.GroupBy) {
+	out, err := createOutput()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer out.Close()
+
+	stream := report.NewNDJSONWriter(out)
+	onFile := stream.WriteFile
+	var changed []*model.FileInfo
+	rollup := analyzer.NewGrouper(directoryRollup)
+	if provider == ci.ProviderGitHub {
+		onFile = func(file *model.FileInfo) {
+			rollup.Add(file)
+			if file.IsChanged {
+				changed = append(changed, file)
+			}
+			stream.WriteFile(file)
+		}
+	}
+
+	stats, grouping := analyzePath(cmd, args, onFile)
+	if err := stream.Finish(stats, buildMetadata(path, grouping)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if provider == ci.ProviderGitHub {
+		sort.Slice(changed, func(i, j int) bool {
+			return changed[i].Path < changed[j].Path
+		})
+		stats.ChangedFiles = changed
+		stats.GroupBy = directoryRollup.String()
+		stats.Groups = rollup.Groups(stats)
+	}
+	return stats, grouping
+}
+
+// runGitHub publishes the results to GitHub Actions: step summary, file
+// annotations and step outputs
+func runGitHub(cmd *cobra.Command, format string, stats *model.Stats, grouping analyzer.GroupBy) error {
+	if !cmd.Flags().Changed("warn-lines") {
+		warnLines = viper.GetInt("warn-lines")
+	}
+	if !cmd.Flags().Changed("warn-additions") {
+		warnAdditions = viper.GetInt("warn-additions")
+	}
+
+	opts := ci.GitHubOptionsFromEnv()
+	opts.MaxLines = warnLines
+	opts.MaxAdditions = warnAdditions
+	opts.Top = topFiles
+
+	// Keep workflow commands out of machine-readable reports on stdout; the
+	// runner reads them from stderr as well
+	opts.Annotations = os.Stdout
+	if outputFile == "" && format != formatStatic {
+		opts.Annotations = os.Stderr
+	}
+
+	r := report.New(stats, buildMetadata(path, grouping))
+	return ci.RunGitHub(withDirectoryRollup(r, stats), opts)
+}
+
+func runBadge(cmd *cobra.Command, args []string) {
+	opts := report.BadgeOptions{
+		Metric:     badgeMetric,
+		Language:   badgeLanguage,
+		Label:      badgeLabel,
+		Color:      badgeColor,
+		LabelColor: badgeLabelColor,
+	}
+	if badgeThresholds != "" {
+		thresholds, err := report.ParseThresholds(badgeThresholds)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		opts.Thresholds = thresholds
+	}
+
+	stats, grouping := analyzePath(cmd, args, nil)
+	r := report.New(stats, buildMetadata(path, grouping))
+
+	out, err := createOutput()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer out.Close()
+
+	if err := report.WriteBadge(out, r, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runCheck(cmd *cobra.Command, args []string) {
+	if checkFormat != "text" && checkFormat != formatSARIF {
+		fmt.Fprintf(os.Stderr, "Error: unknown --format %q (expected text or sarif)\n", checkFormat)
+		os.Exit(1)
+	}
+
+	budgets, rules, err := loadPolicies(cmd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if budgets.IsZero() && len(rules) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: no budgets or rules configured (add a budgets or rules section to .diffloc.yaml or pass --max-additions, --max-deletions, --max-changed-files, --max-net-change or --min-test-ratio)")
+		os.Exit(1)
+	}
+
+	resolvePath(args)
+	if !analyzer.IsGitRepo(path) {
+		fmt.Fprintf(os.Stderr, "Error: %s is not a Git repository; check needs a diff to measure\n", path)
+		os.Exit(1)
+	}
+
+	stats, grouping := analyzePath(cmd, args, nil)
+
+	if checkFormat == formatSARIF {
+		out, err := createOutput()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		err = report.WriteSARIF(out, report.New(stats, buildMetadata(path, grouping)), sarifOptions())
+		out.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		ui.PrintViolations(stats, stats.Violations)
+	}
+
+	if len(stats.Violations) > 0 {
+		os.Exit(exitViolations)
+	}
+}
+
+func runView(cmd *cobra.Command, args []string) {
+	r, err := report.ReadFile(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Stdin holds the report, so there is no keyboard for the TUI
+	format := formatTUI
+	if staticOutput || args[0] == "-" {
+		format = formatStatic
+	}
+
+	if err := writeOutput(format, r.Stats(), analyzer.GroupBy{}); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runCompare(cmd *cobra.Command, args []string) {
+	format := compareFormat
+	if format == "md" {
+		format = formatMarkdown
+	}
+	switch format {
+	case formatTUI, formatStatic:
+		if outputFile != "" {
+			fmt.Fprintf(os.Stderr, "Error: --output is not supported with --format %s\n", format)
+			os.Exit(1)
+		}
+	case formatJSON, formatMarkdown:
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown --format %q (expected tui, static, json or markdown)\n", compareFormat)
+		os.Exit(1)
+	}
+	if compareDepth < 1 {
+		fmt.Fprintln(os.Stderr, "Error: --depth must be at least 1")
+		os.Exit(1)
+	}
+	if args[0] == "-" && args[1] == "-" {
+		fmt.Fprintln(os.Stderr, "Error: only one report can be read from stdin")
+		os.Exit(1)
+	}
+
+	oldReport, err := report.ReadFile(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	newReport, err := report.ReadFile(args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	comparison := analyzer.Compare(oldReport.Stats(), newReport.Stats(), compareDepth)
+
+	// Stdin holds a report, so there is no keyboard for the TUI
+	if format == formatTUI && (args[0] == "-" || args[1] == "-") {
+		format = formatStatic
+	}
+
+	if err := writeComparison(format, comparison, args[0], oldReport, args[1], newReport); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// writeComparison renders a comparison of the reports read from oldName and newName
+func writeComparison(format string, c *model.Comparison, oldName string, oldReport *report.Report, newName string, newReport *report.Report) error {
+	switch format {
+	case formatStatic:
+		return ui.PrintCompare(c, compareLabel(oldName, oldReport), compareLabel(newName, newReport))
+	case formatTUI:
+		if err := ui.RunCompare(c, compareLabel(oldName, oldReport), compareLabel(newName, newReport)); err != nil {
+			return fmt.Errorf("running TUI: %w", err)
+		}
+		return nil
+	}
+
+	out, err := createOutput()
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	r := report.NewComparison(c, oldName, oldReport, newName, newReport)
+	if format == formatMarkdown {
+		return report.WriteComparisonMarkdown(out, r, report.MarkdownOptions{Top: topFiles})
+	}
+	return report.WriteComparisonJSON(out, r)
+}
+
+// compareLabel names a compared report in the TUI by its file name, and its
+// branch or commit when it has one
+func compareLabel(name string, r *report.Report) string {
+	if name == "-" {
+		name = "stdin"
+	}
+	switch {
+	case r.Metadata.Branch != "":
+		return fmt.Sprintf("%s (%s)", filepath.Base(name), r.Metadata.Branch)
+	case len(r.Metadata.HeadSHA) >= 7:
+		return fmt.Sprintf("%s (%s)", filepath.Base(name), r.Metadata.HeadSHA[:7])
+	default:
+		return filepath.Base(name)
+	}
+}
+
+// resolvePath sets path to the target of a command, the current directory by
+// default. An invalid path is fatal.
+func resolvePath(args []string) {
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		path = "."
+	}
+
+	if path == "." {
+		var err error
+		path, err = os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to get current directory: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := analyzer.ValidatePath(path); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// analyzePath resolves the target path and settings shared by all commands,
+// then runs the analysis. A non-nil onFile streams files instead of keeping
+// them in the returned stats. Errors are fatal; a canceled run exits with 130.
+func analyzePath(cmd *cobra.Command, args []string, onFile func(*model.FileInfo)) (*model.Stats, analyzer.GroupBy) {
+	resolvePath(args)
+
+	if shouldWarn, warning := analyzer.ShouldWarnLargeDirectory(path); shouldWarn {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+
 	if !cmd.Flags().Changed("no-gitignore") {
 		noGitignore = viper.GetBool("no-gitignore")
 	}
@@ -148,9 +685,53 @@ func runAnalyze(cmd *cobra.Command, args []string) {
 	if maxDepth == 0 {
 		maxDepth = viper.GetInt("max-depth")
 	}
+	if !cmd.Flags().Changed("go-symbols") {
+		goSymbols = viper.GetBool("go-symbols")
+	}
+	if !cmd.Flags().Changed("ignore-gofmt") {
+		ignoreGofmt = viper.GetBool("ignore-gofmt")
+	}
+	if !cmd.Flags().Changed("complexity") {
+		complexity = viper.GetBool("complexity")
+	}
+	if groupBy == "" {
+		groupBy = viper.GetString("group-by")
+	}
+	if !cmd.Flags().Changed("per-module") {
+		perModule = viper.GetBool("per-module")
+	}
+	if !cmd.Flags().Changed("affected-modules") {
+		affectedOnly = viper.GetBool("affected-modules")
+	}
+	if coverageStrip == "" {
+		coverageStrip = viper.GetString("coverage-strip-prefix")
+	}
+	if perModule && groupBy == "" {
+		groupBy = analyzer.GroupByModule
+	}
+
+	var grouping analyzer.GroupBy
+	if groupBy != "" {
+		var err error
+		grouping, err = analyzer.ParseGroupBy(groupBy)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	filter := analyzer.NewFilter(allowedExts, customExcludes, !noGitignore, excludeTests)
 
+	var testPatterns map[string][]string
+	if err := viper.UnmarshalKey("tests", &testPatterns); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid tests in config: %v\n", err)
+		os.Exit(1)
+	}
+	if err := filter.SetTestPatterns(testPatterns); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	if !noGitignore && analyzer.IsGitRepo(path) {
 		repoRoot, err := analyzer.GetRepoRoot(path)
 		if err == nil {
@@ -158,6 +739,29 @@ func runAnalyze(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	var coverageSource analyzer.CoverageSource
+	if len(coverProfiles) > 0 {
+		profile, err := loadCoverage(filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		coverageSource = profile
+	}
+
+	check, err := newPolicyCheck(cmd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if stream := onFile; stream != nil {
+		// Streamed files are not kept, so check them on the way out
+		onFile = func(file *model.FileInfo) {
+			check.add(file)
+			stream(file)
+		}
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -169,7 +773,18 @@ func runAnalyze(cmd *cobra.Command, args []string) {
 		cancel()
 	}()
 
-	stats, err := analyzer.Analyze(ctx, path, filter)
+	opts := analyzer.Options{
+		GoSymbols:           goSymbols,
+		IgnoreGoFormat:      ignoreGofmt,
+		Complexity:          complexity,
+		Modules:             perModule || grouping.Kind == analyzer.GroupByModule,
+		AffectedModulesOnly: affectedOnly,
+		Hunks:               hunks,
+		Coverage:            coverageSource,
+		OnFile:              onFile,
+	}
+
+	stats, err := analyzer.Analyze(ctx, path, filter, opts)
 	if err != nil {
 		if err == context.Canceled {
 			fmt.Fprintln(os.Stderr, "\nAnalysis canceled by user")
@@ -179,37 +794,349 @@ func runAnalyze(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	if jsonOutput {
-		output, err := json.MarshalIndent(stats, "", "  ")
+	if groupBy != "" {
+		stats.GroupBy = grouping.String()
+		stats.Groups = analyzer.GroupFiles(stats, grouping)
+	}
+
+	if onFile == nil {
+		for _, files := range [][]*model.FileInfo{stats.ChangedFiles, stats.UnchangedFiles} {
+			for _, file := range files {
+				check.add(file)
+			}
+		}
+	}
+	if err := check.finish(stats); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	return stats, grouping
+}
+
+// loadCoverage reads the --coverprofile reports, mapping their files onto
+// repository paths
+func loadCoverage(filter *analyzer.Filter) (*coverage.Profile, error) {
+	if !analyzer.IsGitRepo(path) {
+		return nil, fmt.Errorf("--coverprofile needs a Git repository to find the changed lines")
+	}
+
+	root, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	modules, err := analyzer.DetectModules(path, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return coverage.Load(coverProfiles, coverage.Options{
+		Root:        root,
+		StripPrefix: coverageStrip,
+		Modules:     modules.List(),
+	})
+}
+
+// loadPolicies reads the budgets and rules sections of the config file. The
+// --max-* flags of check override the top-level budget.
+func loadPolicies(cmd *cobra.Command) (policy.Budgets, []policy.FileRule, error) {
+	var budgets policy.Budgets
+	if err := viper.UnmarshalKey("budgets", &budgets); err != nil {
+		return budgets, nil, fmt.Errorf("invalid budgets in config: %w", err)
+	}
+
+	if cmd.Flags().Changed("max-additions") {
+		budgets.MaxAdditions = &maxAdditions
+	}
+	if cmd.Flags().Changed("max-deletions") {
+		budgets.MaxDeletions = &maxDeletions
+	}
+	if cmd.Flags().Changed("max-changed-files") {
+		budgets.MaxChangedFiles = &maxChangedFiles
+	}
+	if cmd.Flags().Changed("max-net-change") {
+		budgets.MaxNetChange = &maxNetChange
+	}
+	if cmd.Flags().Changed("min-test-ratio") {
+		budgets.MinTestRatio = &minTestRatio
+	}
+
+	var rules []policy.FileRule
+	if err := viper.UnmarshalKey("rules", &rules); err != nil {
+		return budgets, nil, fmt.Errorf("invalid rules in config: %w", err)
+	}
+
+	return budgets, rules, nil
+}
+
+// policyCheck collects the budget and rule violations of the analyzed files.
+// Budgets need a diff, so they only apply to Git repositories, where rules are
+// also limited to changed files: an existing oversized file is reported once
+// it is touched.
+type policyCheck struct {
+	budgets    policy.Budgets
+	rules      *policy.RuleSet
+	git        bool
+	changed    []*model.FileInfo // Kept for budgets, which total every changed file
+	violations []*model.Violation
+}
+
+func newPolicyCheck(cmd *cobra.Command) (*policyCheck, error) {
+	budgets, rules, err := loadPolicies(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	set, err := policy.CompileRules(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return &policyCheck{budgets: budgets, rules: set, git: analyzer.IsGitRepo(path)}, nil
+}
+
+// add checks a file against the rules, as the analysis reports it
+func (p *policyCheck) add(file *model.FileInfo) {
+	if file.IsChanged != p.git {
+		return
+	}
+
+	p.violations = append(p.violations, p.rules.Check(file)...)
+	if p.git && !p.budgets.IsZero() {
+		p.changed = append(p.changed, file)
+	}
+}
+
+// finish records the violations in stats, budgets first
+func (p *policyCheck) finish(stats *model.Stats) error {
+	var violations []*model.Violation
+	if p.git && !p.budgets.IsZero() {
+		var err error
+		violations, err = policy.CheckBudgets(p.changed, p.budgets)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to marshal JSON: %v\n", err)
-			os.Exit(1)
+			return err
 		}
-		fmt.Println(string(output))
-	} else if staticOutput {
+	}
+
+	stats.Violations = append(violations, p.violations...)
+	return nil
+}
+
+// sarifOptions describes the configuration file for SARIF locations of aggregate budgets
+func sarifOptions() report.SARIFOptions {
+	opts := report.SARIFOptions{InformationURI: "https://github.com/nodelike/diffloc"}
+
+	if config := viper.ConfigFileUsed(); config != "" {
+		if abs, err := filepath.Abs(config); err == nil {
+			if root, err := filepath.Abs(path); err == nil {
+				if rel, err := filepath.Rel(root, abs); err == nil && !strings.HasPrefix(rel, "..") {
+					opts.ConfigPath = filepath.ToSlash(rel)
+				}
+			}
+		}
+	}
+	return opts
+}
+
+// resolveFormat determines the output format from --format and the --json/--static shorthands
+func resolveFormat() (string, error) {
+	format := outputFormat
+	if format == "" {
+		format = viper.GetString("format")
+	}
+
+	if templateFile != "" || templateString != "" {
+		if templateFile != "" && templateString != "" {
+			return "", fmt.Errorf("--template and --template-string are mutually exclusive")
+		}
+		if jsonOutput || staticOutput || (format != "" && format != formatTemplate) {
+			return "", fmt.Errorf("conflicting output options: --template cannot be combined with --format, --json or --static")
+		}
+		return formatTemplate, nil
+	}
+
+	switch {
+	case jsonOutput && format != "" && format != formatJSON,
+		staticOutput && format != "" && format != formatStatic,
+		jsonOutput && staticOutput:
+		return "", fmt.Errorf("conflicting output options: use a single --format")
+	case jsonOutput:
+		return formatJSON, nil
+	case staticOutput:
+		return formatStatic, nil
+	case format == "":
+		return formatTUI, nil
+	}
+
+	switch format {
+	case formatTUI, formatStatic:
+		if outputFile != "" {
+			return "", fmt.Errorf("--output is not supported with --format %s", format)
+		}
+		return format, nil
+	case formatJSON, formatCSV, formatTSV, formatMarkdown, formatHTML, formatMetrics, formatNDJSON, formatSARIF, formatHunks, formatQuickfix:
+		return format, nil
+	case "prometheus":
+		return formatMetrics, nil
+	case "md":
+		return formatMarkdown, nil
+	case formatTemplate:
+		return "", fmt.Errorf("--format template requires --template or --template-string")
+	default:
+		return "", fmt.Errorf("unknown --format %q (expected tui, static, json, csv, tsv, markdown, html, openmetrics, ndjson, sarif, hunks or quickfix)", format)
+	}
+}
+
+// writeOutput renders stats in the requested format
+func writeOutput(format string, stats *model.Stats, grouping analyzer.GroupBy) error {
+	switch format {
+	case formatStatic:
 		if err := ui.PrintStatic(stats); err != nil {
-			fmt.Fprintf(os.Stderr, "Error printing output: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("printing output: %w", err)
 		}
-	} else {
+		return nil
+	case formatTUI:
 		if err := ui.Run(stats); err != nil {
-			fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("running TUI: %w", err)
 		}
+		return nil
 	}
 
-	if memProfile != "" {
-		f, err := os.Create(memProfile)
+	out, err := createOutput()
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	r := report.New(stats, buildMetadata(path, grouping))
+
+	switch format {
+	case formatCSV:
+		return report.WriteCSV(out, r, summaryRow)
+	case formatTSV:
+		return report.WriteTSV(out, r, summaryRow)
+	case formatMarkdown:
+		return report.WriteMarkdown(out, withDirectoryRollup(r, stats), report.MarkdownOptions{Top: topFiles})
+	case formatHTML:
+		return report.WriteHTML(out, r)
+	case formatMetrics:
+		return report.WriteOpenMetrics(out, r)
+	case formatSARIF:
+		return report.WriteSARIF(out, r, sarifOptions())
+	case formatHunks:
+		return report.WriteHunks(out, r)
+	case formatQuickfix:
+		return report.WriteQuickfix(out, r)
+	case formatTemplate:
+		return report.WriteTemplate(out, userTemplate, report.NewTemplateData(stats, r.Metadata))
+	default:
+		return report.WriteJSON(out, r)
+	}
+}
+
+// createOutput opens the --output file, or returns stdout when none is given
+func createOutput() (io.WriteCloser, error) {
+	if outputFile == "" {
+		return nopCloser{os.Stdout}, nil
+	}
+
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+	return f, nil
+}
+
+// nopCloser keeps stdout open when the output is closed
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+// loadTemplate parses the template given with --template or --template-string.
+// Inline templates get a trailing newline so one-liners print cleanly in shells.
+func loadTemplate() (*template.Template, error) {
+	if templateString != "" {
+		text := templateString
+		if !strings.HasSuffix(text, "\n") {
+			text += "\n"
+		}
+		tmpl, err := report.ParseTemplate("template-string", text)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to create memory profile: %v\n", err)
-			os.Exit(1)
+			return nil, fmt.Errorf("invalid --template-string: %w", err)
 		}
-		defer f.Close()
-		if err := pprof.WriteHeapProfile(f); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to write memory profile: %v\n", err)
-			os.Exit(1)
+		return tmpl, nil
+	}
+
+	content, err := os.ReadFile(templateFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	tmpl, err := report.ParseTemplate(filepath.Base(templateFile), string(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// directoryRollup groups Markdown reports and step summaries without --group-by
+var directoryRollup = analyzer.GroupBy{Kind: analyzer.GroupByDir, Depth: 1}
+
+// withDirectoryRollup adds a top-level directory rollup to reports without --group-by
+func withDirectoryRollup(r *report.Report, stats *model.Stats) *report.Report {
+	if r.Groups != nil {
+		return r
+	}
+
+	grouped := *stats
+	grouped.GroupBy = directoryRollup.String()
+	grouped.Groups = analyzer.GroupFiles(stats, directoryRollup)
+	r.Groups = report.New(&grouped, r.Metadata).Groups
+	return r
+}
+
+// buildMetadata describes the current run for machine-readable reports
+func buildMetadata(path string, grouping analyzer.GroupBy) report.Metadata {
+	repoRoot, err := filepath.Abs(path)
+	if err != nil {
+		repoRoot = path
+	}
+
+	meta := report.Metadata{
+		Tool:        "diffloc",
+		ToolVersion: rootCmd.Version,
+		GeneratedAt: time.Now().UTC().Truncate(time.Second),
+		RepoRoot:    repoRoot,
+		Filter: report.Filter{
+			Extensions:       allowedExts,
+			Excludes:         customExcludes,
+			ExcludeTests:     excludeTests,
+			RespectGitignore: !noGitignore,
+			MaxDepth:         maxDepth,
+		},
+		Options: report.Options{
+			GoSymbols:       goSymbols,
+			IgnoreGofmt:     ignoreGofmt,
+			Complexity:      complexity,
+			AffectedModules: affectedOnly,
+			Hunks:           hunks,
+		},
+	}
+
+	if groupBy != "" {
+		meta.Options.GroupBy = grouping.String()
+	}
+
+	if analyzer.IsGitRepo(path) {
+		meta.Git = true
+		meta.BaseRef = "HEAD"
+		if branch, sha, err := analyzer.GetHeadInfo(path); err == nil {
+			meta.Branch = branch
+			meta.HeadSHA = sha
 		}
 	}
+
+	return meta
 }
 
 func main() {
@@ -2,13 +2,98 @@ package analyzer
 
 import (
 	"context"
+	"sort"
 
 	"github.com/nodelike/diffloc/internal/model"
 )
 
+// Options enables optional analysis passes
+type Options struct {
+	// GoSymbols reports the top-level Go declarations touched by each changed .go file
+	GoSymbols bool
+	// IgnoreGoFormat normalizes both sides of changed .go files with go/format before diffing
+	IgnoreGoFormat bool
+	// Complexity computes per-file complexity metrics and their change against HEAD
+	Complexity bool
+	// Modules attributes every file to its owning module
+	Modules bool
+	// AffectedModulesOnly skips unchanged files in modules the current diff does not touch
+	AffectedModulesOnly bool
+	// Hunks records the changed line ranges of each changed file
+	Hunks bool
+	// Coverage, when set, measures the test coverage of the lines each changed file adds
+	Coverage CoverageSource
+	// OnFile, when set, receives every file as soon as it is counted instead of
+	// the file being kept in the returned Stats, which then only carries totals.
+	// Calls are serialized but arrive in completion order.
+	OnFile func(file *model.FileInfo)
+}
+
+// detectModules runs module detection when the options require it
+func (o Options) detectModules(rootPath string, filter *Filter) (*ModuleSet, error) {
+	if !o.Modules && !o.AffectedModulesOnly {
+		return nil, nil
+	}
+	return DetectModules(rootPath, filter)
+}
+
+// record adds a counted file to the totals of stats and either keeps it or
+// streams it to OnFile. Callers must hold the stats lock.
+func (o Options) record(stats *model.Stats, file *model.FileInfo) {
+	stats.TotalLines += file.Lines
+	stats.TotalAdditions += file.Additions
+	stats.TotalDeletions += file.Deletions
+	stats.TotalComplexity += file.Complexity
+	stats.ComplexityDelta += file.ComplexityDelta
+
+	if o.Coverage != nil && file.IsChanged {
+		measureCoverage(file, o.Coverage)
+		if file.Coverage != nil {
+			addCoverage(stats, file.Coverage)
+		}
+	}
+	if !o.Hunks {
+		// Only computed for coverage
+		file.Hunks = nil
+	}
+
+	if file.IsTest {
+		stats.TestLines += file.Lines
+		stats.TestAdditions += file.Additions
+	} else {
+		stats.ProdLines += file.Lines
+		stats.ProdAdditions += file.Additions
+	}
+
+	if file.IsChanged {
+		stats.ChangedCount++
+	} else {
+		stats.UnchangedCount++
+	}
+
+	switch {
+	case o.OnFile != nil:
+		o.OnFile(file)
+	case file.IsChanged:
+		stats.ChangedFiles = append(stats.ChangedFiles, file)
+	default:
+		stats.UnchangedFiles = append(stats.UnchangedFiles, file)
+	}
+}
+
+// needsContent reports whether the enabled passes need the full contents of path
+func (o Options) needsContent(path string) bool {
+	return o.Complexity || (o.GoSymbols && isGoFile(path))
+}
+
+// needsHunks reports whether the enabled passes need the changed line ranges of each file
+func (o Options) needsHunks() bool {
+	return o.Hunks || o.Coverage != nil
+}
+
 // Analyzer defines the interface for analyzing file statistics
 type Analyzer interface {
-	Analyze(ctx context.Context, rootPath string, filter *Filter) (*model.Stats, error)
+	Analyze(ctx context.Context, rootPath string, filter *Filter, opts Options) (*model.Stats, error)
 }
 
 // GitAnalyzer implements Analyzer for Git repositories
@@ -19,8 +104,8 @@ func NewGitAnalyzer() *GitAnalyzer {
 	return &GitAnalyzer{}
 }
 
-func (g *GitAnalyzer) Analyze(ctx context.Context, rootPath string, filter *Filter) (*model.Stats, error) {
-	return AnalyzeGit(ctx, rootPath, filter)
+func (g *GitAnalyzer) Analyze(ctx context.Context, rootPath string, filter *Filter, opts Options) (*model.Stats, error) {
+	return AnalyzeGit(ctx, rootPath, filter, opts)
 }
 
 // FileAnalyzer implements Analyzer for non-Git directories
@@ -30,8 +115,8 @@ func NewFileAnalyzer() *FileAnalyzer {
 	return &FileAnalyzer{}
 }
 
-func (f *FileAnalyzer) Analyze(ctx context.Context, rootPath string, filter *Filter) (*model.Stats, error) {
-	return AnalyzeFiles(ctx, rootPath, filter)
+func (f *FileAnalyzer) Analyze(ctx context.Context, rootPath string, filter *Filter, opts Options) (*model.Stats, error) {
+	return AnalyzeFiles(ctx, rootPath, filter, opts)
 }
 
 // GetAnalyzer returns the appropriate analyzer based on whether the path is a Git repository
@@ -42,3 +127,9 @@ func GetAnalyzer(rootPath string) Analyzer {
 	return NewFileAnalyzer()
 }
 
+// sortByPath orders files by path so results do not depend on worker scheduling
+func sortByPath(files []*model.FileInfo) {
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+}
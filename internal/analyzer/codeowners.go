@@ -0,0 +1,145 @@
+package analyzer
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gobwas/glob"
+)
+
+// NoOwner is the bucket name for files no CODEOWNERS rule assigns
+const NoOwner = "(unowned)"
+
+// codeownersLocations lists where GitHub looks for CODEOWNERS, in priority order
+var codeownersLocations = []string{
+	filepath.Join(".github", "CODEOWNERS"),
+	"CODEOWNERS",
+	filepath.Join("docs", "CODEOWNERS"),
+}
+
+// Codeowners holds the ownership rules of a CODEOWNERS file
+type Codeowners struct {
+	rules []codeownersRule
+}
+
+type codeownersRule struct {
+	pattern codeownersPattern
+	owners  []string
+}
+
+// codeownersPattern matches a path against any of the globs a CODEOWNERS
+// pattern expands to. The globs are kept apart because gobwas/glob mishandles
+// alternations that mix * and **.
+type codeownersPattern []glob.Glob
+
+// Match reports whether path matches the pattern
+func (p codeownersPattern) Match(path string) bool {
+	for _, g := range p {
+		if g.Match(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadCodeowners parses the first CODEOWNERS file found in .github/, the
+// repository root or docs/. Returns nil without error if there is none.
+func LoadCodeowners(rootPath string) (*Codeowners, error) {
+	for _, location := range codeownersLocations {
+		file, err := os.Open(filepath.Join(rootPath, location))
+		if err != nil {
+			continue
+		}
+		defer file.Close()
+
+		return parseCodeowners(file)
+	}
+
+	return nil, nil
+}
+
+// parseCodeowners reads CODEOWNERS rules. Lines with invalid patterns are skipped.
+func parseCodeowners(r io.Reader) (*Codeowners, error) {
+	c := &Codeowners{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if idx := strings.Index(line, " #"); idx != -1 {
+			line = line[:idx]
+		}
+
+		fields := strings.Fields(line)
+		pattern, err := compileCodeownersPattern(fields[0])
+		if err != nil {
+			continue
+		}
+		c.rules = append(c.rules, codeownersRule{pattern: pattern, owners: fields[1:]})
+	}
+
+	return c, scanner.Err()
+}
+
+// Owners returns the owners of path. As on GitHub, the last matching rule
+// wins, and a matching rule without owners leaves the file unowned.
+func (c *Codeowners) Owners(path string) []string {
+	if c == nil {
+		return nil
+	}
+
+	path = filepath.ToSlash(path)
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].pattern.Match(path) {
+			return c.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// compileCodeownersPattern compiles a CODEOWNERS pattern using GitHub's
+// gitignore-style semantics: a leading or inner slash anchors the pattern to
+// the repository root, otherwise it matches at any depth. Directory patterns,
+// with a trailing slash or a last component without wildcards, cover
+// everything below the directory; patterns such as "docs/*" only match at
+// their own level.
+func compileCodeownersPattern(pattern string) (codeownersPattern, error) {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	trimmed := strings.Trim(pattern, "/")
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(trimmed, "/")
+	literalName := !strings.ContainsAny(trimmed[strings.LastIndex(trimmed, "/")+1:], "*?[")
+
+	bases := []string{trimmed}
+	if !anchored {
+		bases = append(bases, "**/"+trimmed)
+	}
+	if strings.HasPrefix(trimmed, "**/") {
+		bases = append(bases, strings.TrimPrefix(trimmed, "**/"))
+	}
+
+	alternatives := make([]string, 0, len(bases)*2)
+	for _, base := range bases {
+		if !dirOnly {
+			alternatives = append(alternatives, base)
+		}
+		if dirOnly || literalName {
+			alternatives = append(alternatives, base+"/**")
+		}
+	}
+
+	compiled := make(codeownersPattern, 0, len(alternatives))
+	for _, alternative := range alternatives {
+		g, err := glob.Compile(alternative, '/')
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, g)
+	}
+	return compiled, nil
+}
@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompileCodeownersPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		// Wildcards
+		{"*", "main.go", true},
+		{"*", "internal/ui/tui.go", true},
+		{"*.js", "app.js", true},
+		{"*.js", "web/src/app.js", true},
+		{"*.js", "app.go", false},
+		{"docs/*", "docs/a.md", true},
+		{"docs/*", "docs/sub/b.md", false},
+		{"/docs/*.md", "docs/a.md", true},
+		{"/docs/*.md", "docs/sub/a.md", false},
+
+		// Double asterisks
+		{"**/logs", "logs/a.log", true},
+		{"**/logs", "build/logs/a.log", true},
+		{"**/logs", "build/logs", true},
+		{"/scripts/**", "scripts/a/b.sh", true},
+		{"/scripts/**", "src/scripts/a.sh", false},
+
+		// Anchored paths
+		{"/docs", "docs", true},
+		{"/docs", "docs/a.md", true},
+		{"/docs", "src/docs/a.md", false},
+		{"build/logs", "build/logs/a.log", true},
+		{"build/logs", "src/build/logs/a.log", false},
+		{"/Makefile", "Makefile", true},
+		{"/Makefile", "sub/Makefile", false},
+
+		// Unanchored names match at any depth
+		{"docs", "docs/a.md", true},
+		{"docs", "src/docs/a.md", true},
+		{"Makefile", "sub/Makefile", true},
+
+		// Directory patterns
+		{"apps/", "apps/web/index.js", true},
+		{"apps/", "src/apps/index.js", true},
+		{"apps/", "apps", false},
+		{"/build/logs/", "build/logs/a.log", true},
+		{"/build/logs/", "src/build/logs/a.log", false},
+	}
+
+	for _, tt := range tests {
+		g, err := compileCodeownersPattern(tt.pattern)
+		if err != nil {
+			t.Fatalf("compileCodeownersPattern(%q): %v", tt.pattern, err)
+		}
+		if got := g.Match(tt.path); got != tt.want {
+			t.Errorf("pattern %q on %q = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCodeownersOwners(t *testing.T) {
+	c, err := parseCodeowners(strings.NewReader(`
+# Default owners
+*                @org/core
+
+docs/*           @org/docs    # direct children only
+/internal/ui/    @alice @bob
+*.md             @org/writers
+/internal/ui/generated/
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"main.go", []string{"@org/core"}},
+		{"docs/setup.txt", []string{"@org/docs"}},
+		{"docs/sub/setup.txt", []string{"@org/core"}},
+		{"internal/ui/tui.go", []string{"@alice", "@bob"}},
+		{"internal/ui/README.md", []string{"@org/writers"}},
+		{"internal/ui/generated/x.go", []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := c.Owners(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Owners(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCodeownersNil(t *testing.T) {
+	var c *Codeowners
+	if got := c.Owners("main.go"); got != nil {
+		t.Errorf("Owners on nil Codeowners = %q, want nil", got)
+	}
+}
@@ -0,0 +1,118 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+// Compare describes how the tree changed between two analyses, typically
+// release snapshots. Every file present in an analysis counts, changed or
+// not; files an analysis reports as deleted are not part of its tree.
+// Directories are rolled up to depth components.
+func Compare(oldStats, newStats *model.Stats, depth int) *model.Comparison {
+	oldLines := treeLines(oldStats)
+	newLines := treeLines(newStats)
+
+	c := &model.Comparison{Total: model.GroupDelta{Name: "total"}}
+	dirs := make(map[string]*model.GroupDelta)
+	langs := make(map[string]*model.GroupDelta)
+
+	count := func(path string, lines int, side func(d *model.Delta) *int) {
+		*side(&c.Total.Files)++
+		*side(&c.Total.Lines) += lines
+		for _, bucket := range []*model.GroupDelta{
+			groupDelta(dirs, dirPrefix(path, depth)),
+			groupDelta(langs, Language(path)),
+		} {
+			*side(&bucket.Files)++
+			*side(&bucket.Lines) += lines
+		}
+	}
+	oldSide := func(d *model.Delta) *int { return &d.Old }
+	newSide := func(d *model.Delta) *int { return &d.New }
+
+	for path, lines := range oldLines {
+		count(path, lines, oldSide)
+	}
+	for path, lines := range newLines {
+		count(path, lines, newSide)
+
+		oldCount, existed := oldLines[path]
+		switch {
+		case !existed:
+			c.AddedFiles++
+			c.Files = append(c.Files, &model.FileDelta{Path: path, Status: model.StatusAdded, Lines: model.Delta{New: lines}})
+		case oldCount != lines:
+			c.ModifiedFiles++
+			c.Files = append(c.Files, &model.FileDelta{Path: path, Status: model.StatusModified, Lines: model.Delta{Old: oldCount, New: lines}})
+		default:
+			c.UnchangedFiles++
+		}
+	}
+	for path, lines := range oldLines {
+		if _, ok := newLines[path]; !ok {
+			c.DeletedFiles++
+			c.Files = append(c.Files, &model.FileDelta{Path: path, Status: model.StatusDeleted, Lines: model.Delta{Old: lines}})
+		}
+	}
+
+	sort.Slice(c.Files, func(i, j int) bool {
+		ci, cj := abs(c.Files[i].Lines.Change()), abs(c.Files[j].Lines.Change())
+		if ci != cj {
+			return ci > cj
+		}
+		return c.Files[i].Path < c.Files[j].Path
+	})
+	c.Directories = sortedGroupDeltas(dirs)
+	c.Languages = sortedGroupDeltas(langs)
+
+	return c
+}
+
+// treeLines returns the line count of every file present in the analyzed tree
+func treeLines(stats *model.Stats) map[string]int {
+	lines := make(map[string]int, len(stats.ChangedFiles)+len(stats.UnchangedFiles))
+	for _, files := range [][]*model.FileInfo{stats.ChangedFiles, stats.UnchangedFiles} {
+		for _, file := range files {
+			if file.Status != model.StatusDeleted {
+				lines[filepath.ToSlash(file.Path)] = file.Lines
+			}
+		}
+	}
+	return lines
+}
+
+// groupDelta returns the bucket named name, creating it on first use
+func groupDelta(groups map[string]*model.GroupDelta, name string) *model.GroupDelta {
+	group, ok := groups[name]
+	if !ok {
+		group = &model.GroupDelta{Name: name}
+		groups[name] = group
+	}
+	return group
+}
+
+// sortedGroupDeltas orders buckets by the size of their line change, then by name
+func sortedGroupDeltas(groups map[string]*model.GroupDelta) []*model.GroupDelta {
+	list := make([]*model.GroupDelta, 0, len(groups))
+	for _, group := range groups {
+		list = append(list, group)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		ci, cj := abs(list[i].Lines.Change()), abs(list[j].Lines.Change())
+		if ci != cj {
+			return ci > cj
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
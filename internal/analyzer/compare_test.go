@@ -0,0 +1,105 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+func TestCompare(t *testing.T) {
+	oldStats := &model.Stats{
+		ChangedFiles: []*model.FileInfo{
+			{Path: "internal/a/a.go", Lines: 100, Status: model.StatusModified},
+			{Path: "gone.go", Lines: 0, Status: model.StatusDeleted},
+		},
+		UnchangedFiles: []*model.FileInfo{
+			{Path: "main.go", Lines: 10, Status: model.StatusUnchanged},
+			{Path: "internal/b/b.go", Lines: 50, Status: model.StatusUnchanged},
+			{Path: "setup.py", Lines: 5, Status: model.StatusUnchanged},
+		},
+	}
+	newStats := &model.Stats{
+		ChangedFiles: []*model.FileInfo{
+			{Path: "web/app.js", Lines: 30, Status: model.StatusAdded},
+			{Path: "setup.py", Lines: 0, Status: model.StatusDeleted},
+		},
+		UnchangedFiles: []*model.FileInfo{
+			{Path: "main.go", Lines: 10, Status: model.StatusUnchanged},
+			{Path: "internal/a/a.go", Lines: 120, Status: model.StatusUnchanged},
+		},
+	}
+
+	c := Compare(oldStats, newStats, 1)
+
+	wantTotal := model.GroupDelta{Name: "total", Files: model.Delta{Old: 4, New: 3}, Lines: model.Delta{Old: 165, New: 160}}
+	if !reflect.DeepEqual(c.Total, wantTotal) {
+		t.Errorf("Total = %+v, want %+v", c.Total, wantTotal)
+	}
+	if c.AddedFiles != 1 || c.DeletedFiles != 2 || c.ModifiedFiles != 1 || c.UnchangedFiles != 1 {
+		t.Errorf("added, deleted, modified, unchanged = %d, %d, %d, %d, want 1, 2, 1, 1",
+			c.AddedFiles, c.DeletedFiles, c.ModifiedFiles, c.UnchangedFiles)
+	}
+
+	// Files are ordered by the size of their line change; unchanged files are left out
+	wantFiles := []*model.FileDelta{
+		{Path: "internal/b/b.go", Status: model.StatusDeleted, Lines: model.Delta{Old: 50}},
+		{Path: "web/app.js", Status: model.StatusAdded, Lines: model.Delta{New: 30}},
+		{Path: "internal/a/a.go", Status: model.StatusModified, Lines: model.Delta{Old: 100, New: 120}},
+		{Path: "setup.py", Status: model.StatusDeleted, Lines: model.Delta{Old: 5}},
+	}
+	if !reflect.DeepEqual(c.Files, wantFiles) {
+		t.Errorf("Files = %v, want %v", deltaPaths(c.Files), deltaPaths(wantFiles))
+	}
+
+	wantDirs := []*model.GroupDelta{
+		{Name: "internal", Files: model.Delta{Old: 2, New: 1}, Lines: model.Delta{Old: 150, New: 120}},
+		{Name: "web", Files: model.Delta{New: 1}, Lines: model.Delta{New: 30}},
+		{Name: ".", Files: model.Delta{Old: 2, New: 1}, Lines: model.Delta{Old: 15, New: 10}},
+	}
+	if !reflect.DeepEqual(c.Directories, wantDirs) {
+		t.Errorf("Directories = %v, want %v", groupDeltas(c.Directories), groupDeltas(wantDirs))
+	}
+
+	wantLangs := []*model.GroupDelta{
+		{Name: "Go", Files: model.Delta{Old: 3, New: 2}, Lines: model.Delta{Old: 160, New: 130}},
+		{Name: "JavaScript", Files: model.Delta{New: 1}, Lines: model.Delta{New: 30}},
+		{Name: "Python", Files: model.Delta{Old: 1}, Lines: model.Delta{Old: 5}},
+	}
+	if !reflect.DeepEqual(c.Languages, wantLangs) {
+		t.Errorf("Languages = %v, want %v", groupDeltas(c.Languages), groupDeltas(wantLangs))
+	}
+}
+
+func TestCompareDepth(t *testing.T) {
+	stats := &model.Stats{UnchangedFiles: []*model.FileInfo{
+		{Path: "internal/a/a.go", Lines: 1},
+		{Path: "internal/b/b.go", Lines: 2},
+	}}
+
+	c := Compare(&model.Stats{}, stats, 2)
+
+	var names []string
+	for _, d := range c.Directories {
+		names = append(names, d.Name)
+	}
+	if want := []string{"internal/b", "internal/a"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("directories at depth 2 = %v, want %v", names, want)
+	}
+}
+
+func deltaPaths(files []*model.FileDelta) []string {
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.Path+" "+f.Status)
+	}
+	return paths
+}
+
+func groupDeltas(groups []*model.GroupDelta) []model.GroupDelta {
+	var list []model.GroupDelta
+	for _, g := range groups {
+		list = append(list, *g)
+	}
+	return list
+}
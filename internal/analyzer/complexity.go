@@ -0,0 +1,141 @@
+package analyzer
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+)
+
+// fileComplexity reads a file from disk and computes its complexity.
+// Unreadable and binary files yield 0.
+func fileComplexity(fullPath string) int {
+	content, err := os.ReadFile(fullPath)
+	if err != nil || bytes.IndexByte(content, 0) != -1 {
+		return 0
+	}
+	return ComputeComplexity(fullPath, string(content))
+}
+
+// ComputeComplexity estimates the complexity of a source file. Go files get the
+// sum of the cyclomatic complexity of their functions; other languages (and Go
+// files that fail to parse) fall back to an indentation-based estimate.
+func ComputeComplexity(path, src string) int {
+	if strings.TrimSpace(src) == "" {
+		return 0
+	}
+
+	if isGoFile(path) {
+		if complexity, err := goComplexity(src); err == nil {
+			return complexity
+		}
+	}
+
+	return indentComplexity(src)
+}
+
+// goComplexity returns the total cyclomatic complexity of all functions in src
+func goComplexity(src string) (int, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+			total += cyclomatic(fn.Body)
+		}
+	}
+
+	return total, nil
+}
+
+// cyclomatic computes McCabe's cyclomatic complexity for a function body:
+// one plus the number of branch points and short-circuit operators.
+func cyclomatic(body *ast.BlockStmt) int {
+	complexity := 1
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			complexity++
+		case *ast.CaseClause:
+			if node.List != nil {
+				complexity++
+			}
+		case *ast.CommClause:
+			if node.Comm != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			if node.Op == token.LAND || node.Op == token.LOR {
+				complexity++
+			}
+		}
+		return true
+	})
+
+	return complexity
+}
+
+// indentComplexity approximates complexity for languages without a parser by
+// counting the lines that open a deeper indentation level. Each nested block
+// (function body, branch, loop) contributes one, which tracks cyclomatic
+// complexity closely for indentation-formatted code.
+func indentComplexity(src string) int {
+	lines := strings.Split(src, "\n")
+
+	spaceWidth := 0
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		spaces := len(line) - len(strings.TrimLeft(line, " "))
+		if spaces > 0 && (spaceWidth == 0 || spaces < spaceWidth) {
+			spaceWidth = spaces
+		}
+	}
+	if spaceWidth < 2 {
+		spaceWidth = 2
+	}
+
+	complexity := 0
+	prevLevel := 0
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || isCommentLine(trimmed) {
+			continue
+		}
+
+		level := 0
+		for _, r := range line {
+			if r == '\t' {
+				level += spaceWidth
+			} else if r == ' ' {
+				level++
+			} else {
+				break
+			}
+		}
+		level /= spaceWidth
+
+		if level > prevLevel {
+			complexity++
+		}
+		prevLevel = level
+	}
+
+	return complexity
+}
+
+// isCommentLine reports whether a trimmed line is a comment in common languages
+func isCommentLine(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "//") ||
+		strings.HasPrefix(trimmed, "#") ||
+		strings.HasPrefix(trimmed, "/*") ||
+		strings.HasPrefix(trimmed, "*")
+}
@@ -0,0 +1,45 @@
+package analyzer
+
+import "github.com/nodelike/diffloc/internal/model"
+
+// CoverageSource provides line coverage for repository files, such as a parsed coverage report
+type CoverageSource interface {
+	// Lines maps the executable lines of path to whether they are covered.
+	// ok is false when the source has no data for the file.
+	Lines(path string) (lines map[int]bool, ok bool)
+}
+
+// measureCoverage sets the coverage of the lines a changed file adds.
+// Files the source knows nothing about are left without coverage.
+func measureCoverage(file *model.FileInfo, source CoverageSource) {
+	lines, ok := source.Lines(file.Path)
+	if !ok || file.Status == model.StatusDeleted {
+		return
+	}
+
+	coverage := &model.Coverage{}
+	for _, hunk := range file.Hunks {
+		for _, line := range hunk.Added {
+			covered, executable := lines[line]
+			switch {
+			case !executable:
+			case covered:
+				coverage.Executable++
+				coverage.Covered++
+			default:
+				coverage.Executable++
+				coverage.Uncovered = append(coverage.Uncovered, line)
+			}
+		}
+	}
+	file.Coverage = coverage
+}
+
+// addCoverage adds the coverage of a file to the totals of stats
+func addCoverage(stats *model.Stats, coverage *model.Coverage) {
+	if stats.Coverage == nil {
+		stats.Coverage = &model.Coverage{}
+	}
+	stats.Coverage.Executable += coverage.Executable
+	stats.Coverage.Covered += coverage.Covered
+}
@@ -12,10 +12,21 @@ import (
 )
 
 // AnalyzeFiles analyzes files in a non-git directory
-func AnalyzeFiles(ctx context.Context, rootPath string, filter *Filter) (*model.Stats, error) {
+func AnalyzeFiles(ctx context.Context, rootPath string, filter *Filter, opts Options) (*model.Stats, error) {
+	modules, err := opts.detectModules(rootPath, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	owners, err := LoadCodeowners(rootPath)
+	if err != nil {
+		return nil, err
+	}
+
 	stats := &model.Stats{
 		ChangedFiles:   make([]*model.FileInfo, 0),
 		UnchangedFiles: make([]*model.FileInfo, 0),
+		Modules:        modules.List(),
 	}
 
 	type fileJob struct {
@@ -25,7 +36,7 @@ func AnalyzeFiles(ctx context.Context, rootPath string, filter *Filter) (*model.
 	
 	fileJobs := make([]fileJob, 0)
 	
-	err := filepath.WalkDir(rootPath, func(path string, d os.DirEntry, err error) error {
+	err = filepath.WalkDir(rootPath, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
 			return nil
 		}
@@ -92,11 +103,18 @@ func AnalyzeFiles(ctx context.Context, rootPath string, filter *Filter) (*model.
 				Additions: 0,
 				Deletions: 0,
 				IsChanged: false,
+				Status:    model.StatusUnchanged,
+				IsTest:    filter.IsTest(job.relPath),
+				Module:    modules.Owner(job.relPath),
+				Owners:    owners.Owners(job.relPath),
+			}
+
+			if opts.Complexity {
+				fileInfo.Complexity = fileComplexity(job.fullPath)
 			}
 
 			statsMu.Lock()
-			stats.UnchangedFiles = append(stats.UnchangedFiles, fileInfo)
-			stats.TotalLines += lines
+			opts.record(stats, fileInfo)
 			if bar != nil {
 				bar.Add(1)
 			}
@@ -110,8 +128,9 @@ func AnalyzeFiles(ctx context.Context, rootPath string, filter *Filter) (*model.
 		return nil, err
 	}
 
-	stats.TotalFiles = len(stats.UnchangedFiles)
-	stats.UnchangedCount = len(stats.UnchangedFiles)
+	sortByPath(stats.UnchangedFiles)
+
+	stats.TotalFiles = stats.UnchangedCount
 
 	return stats, nil
 }
@@ -19,6 +19,7 @@ type Filter struct {
 	gitignoreGlobs   []glob.Glob
 	respectGitignore bool
 	excludeTests     bool
+	tests            *TestClassifier
 }
 
 // NewFilter creates a new filter with default or custom settings
@@ -88,18 +89,10 @@ func NewFilter(allowedExts []string, customExcludes []string, respectGitignore b
 		`(^|/)jest-cache($|/)`,
 	}
 
-	testPatterns := []string{
-		`_test\.go$`,
-		`(^|/)tests?($|/)`,
-		`\.test\.(js|ts|jsx|tsx)$`,
-		`\.spec\.(js|ts|jsx|tsx)$`,
-	}
+	// The default test patterns always compile
+	f.tests, _ = NewTestClassifier(nil)
 
-	allPatterns := alwaysExclude
-	if f.excludeTests {
-		allPatterns = append(allPatterns, testPatterns...)
-	}
-	allPatterns = append(allPatterns, customExcludes...)
+	allPatterns := append(alwaysExclude, customExcludes...)
 
 	f.excludePatterns = make([]*regexp.Regexp, 0, len(allPatterns))
 	for _, pattern := range allPatterns {
@@ -111,6 +104,22 @@ func NewFilter(allowedExts []string, customExcludes []string, respectGitignore b
 	return f
 }
 
+// SetTestPatterns overrides the per-language patterns that classify test
+// files, as described by NewTestClassifier
+func (f *Filter) SetTestPatterns(overrides map[string][]string) error {
+	tests, err := NewTestClassifier(overrides)
+	if err != nil {
+		return err
+	}
+	f.tests = tests
+	return nil
+}
+
+// IsTest reports whether path is a test file
+func (f *Filter) IsTest(path string) bool {
+	return f.tests.IsTest(path)
+}
+
 // LoadGitignore parses .gitignore file and loads patterns
 func (f *Filter) LoadGitignore(repoRoot string) error {
 	if !f.respectGitignore {
@@ -160,6 +169,10 @@ func (f *Filter) LoadGitignore(repoRoot string) error {
 func (f *Filter) ShouldInclude(path string) bool {
 	path = filepath.ToSlash(path)
 
+	if f.excludeTests && f.tests.IsTest(path) {
+		return false
+	}
+
 	for _, re := range f.excludePatterns {
 		if re.MatchString(path) {
 			return false
@@ -182,6 +195,31 @@ func (f *Filter) ShouldInclude(path string) bool {
 	return true
 }
 
+// ExcludesDir checks if a directory is excluded by the exclusion or gitignore patterns
+func (f *Filter) ExcludesDir(relPath string) bool {
+	dirPath := strings.TrimSuffix(filepath.ToSlash(relPath), "/") + "/"
+
+	if f.excludeTests && f.tests.IsTest(dirPath) {
+		return true
+	}
+
+	for _, re := range f.excludePatterns {
+		if re.MatchString(dirPath) {
+			return true
+		}
+	}
+
+	if f.respectGitignore {
+		for _, g := range f.gitignoreGlobs {
+			if g.Match(dirPath) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // CountLines counts the number of lines in a file using chunked reading
 // Returns 0 for binary files (detected by null bytes in first chunk)
 func CountLines(filePath string) (int, error) {
@@ -0,0 +1,54 @@
+package analyzer
+
+import "testing"
+
+func TestFilterExcludeTests(t *testing.T) {
+	f := NewFilter(nil, nil, false, true)
+	if err := f.SetTestPatterns(map[string][]string{
+		"Go":        {`_test\.go$`, `(^|/)testdata/`},
+		AnyLanguage: {},
+	}); err != nil {
+		t.Fatalf("SetTestPatterns: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"main.go", true},
+		{"main_test.go", false},
+		{"pkg/testdata/fixture.go", false},
+		{"tests/helpers.go", true},
+		{"app/test_views.py", false},
+		{"web/util.spec.mjs", false},
+		{"web/util.mjs", true},
+	}
+
+	for _, tt := range tests {
+		if got := f.ShouldInclude(tt.path); got != tt.want {
+			t.Errorf("ShouldInclude(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+		if got := f.IsTest(tt.path); got == tt.want {
+			t.Errorf("IsTest(%q) = %v, want %v", tt.path, got, !tt.want)
+		}
+	}
+
+	if f.ExcludesDir("tests") {
+		t.Errorf("ExcludesDir(tests) = true, want false with the \"*\" patterns cleared")
+	}
+}
+
+func TestFilterKeepsTestsByDefault(t *testing.T) {
+	f := NewFilter(nil, nil, false, false)
+	for _, path := range []string{"main_test.go", "tests/helpers.go", "app/test_views.py"} {
+		if !f.ShouldInclude(path) {
+			t.Errorf("ShouldInclude(%q) = false without --exclude-tests", path)
+		}
+	}
+	if f.ExcludesDir("tests") {
+		t.Errorf("ExcludesDir(tests) = true without --exclude-tests")
+	}
+	if !NewFilter(nil, nil, false, true).ExcludesDir("tests") {
+		t.Errorf("ExcludesDir(tests) = false with --exclude-tests")
+	}
+}
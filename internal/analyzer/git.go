@@ -2,6 +2,7 @@ package analyzer
 
 import (
 	"context"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,7 +16,7 @@ import (
 )
 
 // AnalyzeGit analyzes a git repository for changes
-func AnalyzeGit(ctx context.Context, rootPath string, filter *Filter) (*model.Stats, error) {
+func AnalyzeGit(ctx context.Context, rootPath string, filter *Filter, opts Options) (*model.Stats, error) {
 	repo, err := git.PlainOpen(rootPath)
 	if err != nil {
 		return nil, err
@@ -46,12 +47,24 @@ func AnalyzeGit(ctx context.Context, rootPath string, filter *Filter) (*model.St
 		return nil, err
 	}
 
+	modules, err := opts.detectModules(rootPath, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	owners, err := LoadCodeowners(rootPath)
+	if err != nil {
+		return nil, err
+	}
+
 	stats := &model.Stats{
 		ChangedFiles:   make([]*model.FileInfo, 0),
 		UnchangedFiles: make([]*model.FileInfo, 0),
+		Modules:        modules.List(),
 	}
 
 	changedPaths := make(map[string]bool)
+	affectedModules := make(map[string]bool)
 	var statsMu sync.Mutex
 
 	type changedFileJob struct {
@@ -105,25 +118,49 @@ func AnalyzeGit(ctx context.Context, rootPath string, filter *Filter) (*model.St
 				Additions: 0,
 				Deletions: 0,
 				IsChanged: true,
+				IsTest:    filter.IsTest(job.path),
+				Module:    modules.Owner(job.path),
+				Owners:    owners.Owners(job.path),
 			}
 
 			switch {
-			case job.fileStatus.Staging == git.Untracked || job.fileStatus.Worktree == git.Untracked:
+			case job.fileStatus.Staging == git.Untracked || job.fileStatus.Worktree == git.Untracked || job.fileStatus.Staging == git.Added:
+				fileInfo.Status = model.StatusAdded
 				fileInfo.Additions = lines
+				if opts.needsHunks() {
+					fileInfo.Hunks = wholeFileHunk(lines, true)
+				}
+				if opts.needsContent(job.path) {
+					if workContent, err := readWorktreeContent(repo, job.path); err == nil {
+						analyzeContent(fileInfo, "", workContent, opts)
+					}
+				}
 			case job.fileStatus.Worktree == git.Deleted || job.fileStatus.Staging == git.Deleted:
-				fileInfo.Deletions = lines
+				fileInfo.Status = model.StatusDeleted
+				if headContent, err := readHeadContent(headCommit, job.path); err == nil {
+					fileInfo.HeadLines = countContentLines(headContent)
+					fileInfo.Deletions = fileInfo.HeadLines
+					if opts.needsContent(job.path) {
+						analyzeContent(fileInfo, headContent, "", opts)
+					}
+				} else {
+					fileInfo.Deletions = lines
+				}
+				if opts.needsHunks() {
+					fileInfo.Hunks = wholeFileHunk(fileInfo.Deletions, false)
+				}
 				fileInfo.Lines = 0
 			default:
-				additions, deletions := calculateDiffNative(repo, headCommit, job.path)
-				fileInfo.Additions = additions
-				fileInfo.Deletions = deletions
+				fileInfo.Status = model.StatusModified
+				headContent, workContent, err := readFileVersions(repo, headCommit, job.path)
+				if err == nil {
+					diffModified(fileInfo, headContent, workContent, opts)
+				}
 			}
 
 			statsMu.Lock()
-			stats.ChangedFiles = append(stats.ChangedFiles, fileInfo)
-			stats.TotalLines += fileInfo.Lines
-			stats.TotalAdditions += fileInfo.Additions
-			stats.TotalDeletions += fileInfo.Deletions
+			opts.record(stats, fileInfo)
+			affectedModules[fileInfo.Module] = true
 			if bar != nil {
 				bar.Add(1)
 			}
@@ -149,6 +186,10 @@ func AnalyzeGit(ctx context.Context, rootPath string, filter *Filter) (*model.St
 			return nil
 		}
 
+		if opts.AffectedModulesOnly && !affectedModules[modules.Owner(path)] {
+			return nil
+		}
+
 		unchangedPaths = append(unchangedPaths, path)
 		return nil
 	})
@@ -193,11 +234,18 @@ func AnalyzeGit(ctx context.Context, rootPath string, filter *Filter) (*model.St
 				Additions: 0,
 				Deletions: 0,
 				IsChanged: false,
+				Status:    model.StatusUnchanged,
+				IsTest:    filter.IsTest(path),
+				Module:    modules.Owner(path),
+				Owners:    owners.Owners(path),
+			}
+
+			if opts.Complexity {
+				fileInfo.Complexity = fileComplexity(fullPath)
 			}
 
 			statsMu.Lock()
-			stats.UnchangedFiles = append(stats.UnchangedFiles, fileInfo)
-			stats.TotalLines += lines
+			opts.record(stats, fileInfo)
 			if bar2 != nil {
 				bar2.Add(1)
 			}
@@ -211,91 +259,108 @@ func AnalyzeGit(ctx context.Context, rootPath string, filter *Filter) (*model.St
 		return nil, err
 	}
 
-	stats.ChangedCount = len(stats.ChangedFiles)
-	stats.UnchangedCount = len(stats.UnchangedFiles)
+	sortByPath(stats.ChangedFiles)
+	sortByPath(stats.UnchangedFiles)
+
 	stats.TotalFiles = stats.ChangedCount + stats.UnchangedCount
 	stats.NetChange = stats.TotalAdditions - stats.TotalDeletions
 
 	return stats, nil
 }
 
-// calculateDiffNative uses go-git's native diff to calculate additions and deletions
-func calculateDiffNative(repo *git.Repository, headCommit *object.Commit, path string) (additions, deletions int) {
+// readFileVersions returns the HEAD and worktree contents of a tracked file
+func readFileVersions(repo *git.Repository, headCommit *object.Commit, path string) (headContent, workContent string, err error) {
+	headContent, err = readHeadContent(headCommit, path)
+	if err != nil {
+		return "", "", err
+	}
+
+	workContent, err = readWorktreeContent(repo, path)
+	if err != nil {
+		return "", "", err
+	}
+
+	return headContent, workContent, nil
+}
+
+// readHeadContent returns the content of a file as committed at HEAD
+func readHeadContent(headCommit *object.Commit, path string) (string, error) {
 	headTree, err := headCommit.Tree()
 	if err != nil {
-		return 0, 0
+		return "", err
 	}
 
 	headFile, err := headTree.File(path)
 	if err != nil {
-		return 0, 0
+		return "", err
 	}
 
+	return headFile.Contents()
+}
+
+// readWorktreeContent returns the current content of a file in the worktree
+func readWorktreeContent(repo *git.Repository, path string) (string, error) {
 	worktree, err := repo.Worktree()
 	if err != nil {
-		return 0, 0
+		return "", err
 	}
 
-	fs := worktree.Filesystem
-	worktreeFile, err := fs.Open(path)
+	worktreeFile, err := worktree.Filesystem.Open(path)
 	if err != nil {
-		return 0, 0
+		return "", err
 	}
 	defer worktreeFile.Close()
 
-	worktreeContent := strings.Builder{}
-	buf := make([]byte, 4096)
-	for {
-		n, err := worktreeFile.Read(buf)
-		if n > 0 {
-			worktreeContent.Write(buf[:n])
-		}
-		if err != nil {
-			break
-		}
-	}
-
-	headContent, err := headFile.Contents()
+	content, err := io.ReadAll(worktreeFile)
 	if err != nil {
-		return 0, 0
+		return "", err
 	}
 
-	headLines := strings.Split(headContent, "\n")
-	workLines := strings.Split(worktreeContent.String(), "\n")
-
-	additions, deletions = computeLineDiff(headLines, workLines)
-
-	return additions, deletions
+	return string(content), nil
 }
 
-// computeLineDiff computes additions and deletions using a simplified diff algorithm
-func computeLineDiff(oldLines, newLines []string) (additions, deletions int) {
-	oldMap := make(map[string]int)
-	newMap := make(map[string]int)
-
-	for _, line := range oldLines {
-		oldMap[line]++
+// diffModified counts the lines changed between the HEAD and working tree
+// contents of a modified file. With IgnoreGoFormat, Go sources are compared
+// after formatting, but hunks keep the line numbers of the files as they are.
+func diffModified(fileInfo *model.FileInfo, headContent, workContent string, opts Options) {
+	fileInfo.HeadLines = countContentLines(headContent)
+
+	rawHead, rawWork := headContent, workContent
+	formatted := opts.IgnoreGoFormat && isGoFile(fileInfo.Path)
+	if formatted {
+		headContent = normalizeGoSource(headContent)
+		workContent = normalizeGoSource(workContent)
 	}
 
-	for _, line := range newLines {
-		newMap[line]++
+	hunks := diffHunks(headContent, workContent)
+	fileInfo.Additions, fileInfo.Deletions = hunkTotals(hunks)
+	if opts.needsHunks() {
+		if formatted {
+			renumberHunks(hunks, rawLineNumbers(rawHead, headContent), rawLineNumbers(rawWork, workContent))
+		}
+		fileInfo.Hunks = hunks
 	}
+	if opts.needsContent(fileInfo.Path) {
+		analyzeContent(fileInfo, headContent, workContent, opts)
+	}
+}
 
-	for line, oldCount := range oldMap {
-		newCount := newMap[line]
-		if newCount < oldCount {
-			deletions += oldCount - newCount
-		}
+// analyzeContent runs the optional content-based passes over both versions of a
+// changed file. Either side may be empty for added or deleted files.
+func analyzeContent(fileInfo *model.FileInfo, headContent, workContent string, opts Options) {
+	if opts.GoSymbols && isGoFile(fileInfo.Path) {
+		fileInfo.Symbols = DiffGoSymbols(headContent, workContent)
 	}
 
-	for line, newCount := range newMap {
-		oldCount := oldMap[line]
-		if newCount > oldCount {
-			additions += newCount - oldCount
-		}
+	if opts.Complexity {
+		fileInfo.Complexity = ComputeComplexity(fileInfo.Path, workContent)
+		fileInfo.ComplexityDelta = fileInfo.Complexity - ComputeComplexity(fileInfo.Path, headContent)
 	}
+}
 
-	return additions, deletions
+// countContentLines counts lines the same way CountLines does for files on disk
+func countContentLines(content string) int {
+	return strings.Count(content, "\n")
 }
 
 // IsGitRepo checks if the given path is a git repository
@@ -319,10 +384,28 @@ func GetRepoRoot(path string) (string, error) {
 	return worktree.Filesystem.Root(), nil
 }
 
+// GetHeadInfo returns the current branch name (empty when detached) and HEAD commit SHA
+func GetHeadInfo(path string) (branch, sha string, err error) {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return "", "", err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", "", err
+	}
+
+	if head.Name().IsBranch() {
+		branch = head.Name().Short()
+	}
+	return branch, head.Hash().String(), nil
+}
+
 // Analyze is the main entry point that decides between git and non-git analysis
-func Analyze(ctx context.Context, rootPath string, filter *Filter) (*model.Stats, error) {
+func Analyze(ctx context.Context, rootPath string, filter *Filter, opts Options) (*model.Stats, error) {
 	if IsGitRepo(rootPath) {
-		return AnalyzeGit(ctx, rootPath, filter)
+		return AnalyzeGit(ctx, rootPath, filter, opts)
 	}
-	return AnalyzeFiles(ctx, rootPath, filter)
+	return AnalyzeFiles(ctx, rootPath, filter, opts)
 }
@@ -0,0 +1,14 @@
+package analyzer
+
+import "go/format"
+
+// normalizeGoSource formats src with go/format so that pure gofmt changes
+// (whitespace, alignment, import ordering within blocks) diff as identical.
+// Sources that fail to parse are returned unchanged.
+func normalizeGoSource(src string) string {
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return src
+	}
+	return string(formatted)
+}
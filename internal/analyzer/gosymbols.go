@@ -0,0 +1,199 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+// goDecl is a single top-level declaration extracted from a Go source file
+type goDecl struct {
+	key  string
+	kind string
+	name string
+	text string
+}
+
+// isGoFile reports whether path is a Go source file
+func isGoFile(path string) bool {
+	return filepath.Ext(path) == ".go"
+}
+
+// DiffGoSymbols compares the top-level declarations of two versions of a Go file.
+// Either side may be empty for added or deleted files. Returns nil if either
+// version fails to parse.
+func DiffGoSymbols(oldSrc, newSrc string) []*model.SymbolChange {
+	oldPkg, oldDecls, err := parseGoDecls(oldSrc)
+	if err != nil {
+		return nil
+	}
+
+	newPkg, newDecls, err := parseGoDecls(newSrc)
+	if err != nil {
+		return nil
+	}
+
+	pkg := newPkg
+	if pkg == "" {
+		pkg = oldPkg
+	}
+
+	oldByKey := make(map[string]*goDecl, len(oldDecls))
+	for _, d := range oldDecls {
+		oldByKey[d.key] = d
+	}
+
+	newKeys := make(map[string]bool, len(newDecls))
+	changes := make([]*model.SymbolChange, 0)
+
+	for _, d := range newDecls {
+		newKeys[d.key] = true
+
+		old, existed := oldByKey[d.key]
+		if !existed {
+			changes = append(changes, &model.SymbolChange{
+				Package:   pkg,
+				Kind:      d.kind,
+				Name:      d.name,
+				Change:    model.SymbolAdded,
+				Additions: len(declLines(d.text)),
+			})
+			continue
+		}
+
+		if old.text == d.text {
+			continue
+		}
+
+		additions, deletions := hunkTotals(diffHunks(old.text, d.text))
+		changes = append(changes, &model.SymbolChange{
+			Package:   pkg,
+			Kind:      d.kind,
+			Name:      d.name,
+			Change:    model.SymbolModified,
+			Additions: additions,
+			Deletions: deletions,
+		})
+	}
+
+	for _, d := range oldDecls {
+		if newKeys[d.key] {
+			continue
+		}
+		changes = append(changes, &model.SymbolChange{
+			Package:   pkg,
+			Kind:      d.kind,
+			Name:      d.name,
+			Change:    model.SymbolRemoved,
+			Deletions: len(declLines(d.text)),
+		})
+	}
+
+	return changes
+}
+
+// parseGoDecls parses src and returns its package name and top-level declarations
+// in source order. An empty src yields no declarations.
+func parseGoDecls(src string) (string, []*goDecl, error) {
+	if strings.TrimSpace(src) == "" {
+		return "", nil, nil
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return "", nil, err
+	}
+
+	decls := make([]*goDecl, 0, len(file.Decls))
+	seen := make(map[string]int)
+
+	add := func(kind, name string, doc *ast.CommentGroup, node ast.Node) {
+		start := node.Pos()
+		if doc != nil {
+			start = doc.Pos()
+		}
+		text := src[fset.Position(start).Offset:fset.Position(node.End()).Offset]
+
+		key := kind + " " + name
+		seen[key]++
+		if n := seen[key]; n > 1 {
+			key = fmt.Sprintf("%s#%d", key, n)
+		}
+
+		decls = append(decls, &goDecl{key: key, kind: kind, name: name, text: text})
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				add(model.SymbolMethod, receiverTypeName(d.Recv.List[0].Type)+"."+d.Name.Name, d.Doc, d)
+			} else {
+				add(model.SymbolFunc, d.Name.Name, d.Doc, d)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				// A lone spec owns the whole declaration; grouped specs only their own lines
+				var doc *ast.CommentGroup
+				var node ast.Node = spec
+				if len(d.Specs) == 1 {
+					doc = d.Doc
+					node = d
+				}
+
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if s.Doc != nil {
+						doc = s.Doc
+					}
+					add(model.SymbolType, s.Name.Name, doc, node)
+				case *ast.ValueSpec:
+					if s.Doc != nil {
+						doc = s.Doc
+					}
+					kind := model.SymbolVar
+					if d.Tok == token.CONST {
+						kind = model.SymbolConst
+					}
+					for _, name := range s.Names {
+						if name.Name == "_" {
+							continue
+						}
+						add(kind, name.Name, doc, node)
+					}
+				}
+			}
+		}
+	}
+
+	return file.Name.Name, decls, nil
+}
+
+// receiverTypeName returns the base type name of a method receiver, e.g. "*Foo[T]" becomes "Foo"
+func receiverTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(t.X)
+	case *ast.ParenExpr:
+		return receiverTypeName(t.X)
+	case *ast.Ident:
+		return t.Name
+	default:
+		return "?"
+	}
+}
+
+// declLines splits declaration text into lines
+func declLines(text string) []string {
+	return strings.Split(text, "\n")
+}
@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+func TestDiffGoSymbols(t *testing.T) {
+	const oldSrc = `package calc
+
+// Add adds.
+func Add(a, b int) int {
+	return a + b
+}
+
+func Sub(a, b int) int {
+	return a - b
+}
+
+type T struct{}
+
+func (t *T) Name() string { return "t" }
+
+const (
+	A = 1
+	B = 2
+)
+`
+	const newSrc = `package calc
+
+// Add adds.
+func Add(a, b int) int {
+	sum := a + b
+	return sum
+}
+
+type T struct{}
+
+func (t *T) Name() string { return "t" }
+
+func (T) Size() int {
+	return 0
+}
+
+func Name() string { return "" }
+
+const (
+	A = 1
+	B = 3
+)
+`
+
+	want := []model.SymbolChange{
+		{Package: "calc", Kind: model.SymbolFunc, Name: "Add", Change: model.SymbolModified, Additions: 2, Deletions: 1},
+		{Package: "calc", Kind: model.SymbolMethod, Name: "T.Size", Change: model.SymbolAdded, Additions: 3},
+		{Package: "calc", Kind: model.SymbolFunc, Name: "Name", Change: model.SymbolAdded, Additions: 1},
+		{Package: "calc", Kind: model.SymbolConst, Name: "B", Change: model.SymbolModified, Additions: 1, Deletions: 1},
+		{Package: "calc", Kind: model.SymbolFunc, Name: "Sub", Change: model.SymbolRemoved, Deletions: 3},
+	}
+	if got := symbolChanges(DiffGoSymbols(oldSrc, newSrc)); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffGoSymbols =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestDiffGoSymbolsAddedAndDeletedFiles(t *testing.T) {
+	const src = `package calc
+
+var (
+	_       = 0
+	x, y    int
+	enabled = true
+)
+
+type (
+	A int
+	B string
+)
+`
+
+	added := []model.SymbolChange{
+		{Package: "calc", Kind: model.SymbolVar, Name: "x", Change: model.SymbolAdded, Additions: 1},
+		{Package: "calc", Kind: model.SymbolVar, Name: "y", Change: model.SymbolAdded, Additions: 1},
+		{Package: "calc", Kind: model.SymbolVar, Name: "enabled", Change: model.SymbolAdded, Additions: 1},
+		{Package: "calc", Kind: model.SymbolType, Name: "A", Change: model.SymbolAdded, Additions: 1},
+		{Package: "calc", Kind: model.SymbolType, Name: "B", Change: model.SymbolAdded, Additions: 1},
+	}
+	if got := symbolChanges(DiffGoSymbols("", src)); !reflect.DeepEqual(got, added) {
+		t.Errorf("DiffGoSymbols of an added file =\n%+v\nwant\n%+v", got, added)
+	}
+
+	got := symbolChanges(DiffGoSymbols(src, ""))
+	if len(got) != len(added) {
+		t.Fatalf("DiffGoSymbols of a deleted file returned %d changes, want %d", len(got), len(added))
+	}
+	for _, c := range got {
+		if c.Change != model.SymbolRemoved || c.Deletions != 1 || c.Additions != 0 {
+			t.Errorf("DiffGoSymbols of a deleted file: %+v, want a removal of 1 line", c)
+		}
+	}
+}
+
+func TestDiffGoSymbolsInvalidSource(t *testing.T) {
+	if got := DiffGoSymbols("package calc\n", "package calc\nfunc {"); got != nil {
+		t.Errorf("DiffGoSymbols of invalid source = %+v, want nil", got)
+	}
+}
+
+func symbolChanges(changes []*model.SymbolChange) []model.SymbolChange {
+	var list []model.SymbolChange
+	for _, c := range changes {
+		list = append(list, *c)
+	}
+	return list
+}
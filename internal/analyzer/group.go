@@ -0,0 +1,188 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+// Grouping kinds accepted by --group-by
+const (
+	GroupByDir    = "dir"
+	GroupByExt    = "ext"
+	GroupByLang   = "lang"
+	GroupByModule = "module"
+	GroupByOwner  = "owner"
+)
+
+// GroupBy describes how files are aggregated into buckets
+type GroupBy struct {
+	Kind  string
+	Depth int // Directory depth for GroupByDir
+}
+
+// ParseGroupBy parses a --group-by value such as "dir", "dir:2", "ext", "lang", "module" or "owner"
+func ParseGroupBy(spec string) (GroupBy, error) {
+	kind, arg, hasArg := strings.Cut(strings.TrimSpace(spec), ":")
+
+	switch kind {
+	case GroupByDir:
+		depth := 1
+		if hasArg {
+			n, err := strconv.Atoi(arg)
+			if err != nil || n < 1 {
+				return GroupBy{}, fmt.Errorf("invalid directory depth %q in --group-by %s (expected a positive integer)", arg, spec)
+			}
+			depth = n
+		}
+		return GroupBy{Kind: GroupByDir, Depth: depth}, nil
+	case GroupByExt, GroupByLang, GroupByModule, GroupByOwner:
+		if hasArg {
+			return GroupBy{}, fmt.Errorf("--group-by %s does not take an argument", kind)
+		}
+		return GroupBy{Kind: kind}, nil
+	default:
+		return GroupBy{}, fmt.Errorf("unknown --group-by %q (expected dir[:N], ext, lang, module or owner)", spec)
+	}
+}
+
+func (g GroupBy) String() string {
+	if g.Kind == GroupByDir {
+		return fmt.Sprintf("%s:%d", g.Kind, g.Depth)
+	}
+	return g.Kind
+}
+
+// Keys returns the bucket names a file belongs to
+func (g GroupBy) Keys(file *model.FileInfo) []string {
+	path := filepath.ToSlash(file.Path)
+
+	switch g.Kind {
+	case GroupByDir:
+		return []string{dirPrefix(path, g.Depth)}
+	case GroupByExt:
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext == "" {
+			ext = "(none)"
+		}
+		return []string{ext}
+	case GroupByLang:
+		return []string{Language(path)}
+	case GroupByModule:
+		if file.Module == "" {
+			return []string{NoModule}
+		}
+		return []string{file.Module}
+	case GroupByOwner:
+		if len(file.Owners) == 0 {
+			return []string{NoOwner}
+		}
+		return file.Owners
+	default:
+		return []string{path}
+	}
+}
+
+// dirPrefix returns the first depth directory components of a slash-separated
+// file path, or "." for files at the root
+func dirPrefix(path string, depth int) string {
+	dir := filepath.ToSlash(filepath.Dir(path))
+	if dir == "." {
+		return "."
+	}
+
+	parts := strings.Split(dir, "/")
+	if len(parts) > depth {
+		parts = parts[:depth]
+	}
+	return strings.Join(parts, "/")
+}
+
+// GroupFiles aggregates all files in stats into buckets, sorted by line count.
+// Shares are percentages of the total lines and of the total changed lines; a
+// file with several owners counts towards each of them.
+func GroupFiles(stats *model.Stats, by GroupBy) []*model.Group {
+	g := NewGrouper(by)
+	for _, file := range stats.ChangedFiles {
+		g.Add(file)
+	}
+	for _, file := range stats.UnchangedFiles {
+		g.Add(file)
+	}
+	return g.Groups(stats)
+}
+
+// Grouper aggregates files into buckets one at a time, for streamed analyses
+// that do not keep their files
+type Grouper struct {
+	by     GroupBy
+	groups map[string]*model.Group
+}
+
+// NewGrouper creates an empty Grouper
+func NewGrouper(by GroupBy) *Grouper {
+	return &Grouper{by: by, groups: make(map[string]*model.Group)}
+}
+
+// Add counts a file towards its buckets
+func (g *Grouper) Add(file *model.FileInfo) {
+	for _, key := range g.by.Keys(file) {
+		group, ok := g.groups[key]
+		if !ok {
+			group = &model.Group{Name: key}
+			g.groups[key] = group
+		}
+
+		group.Files++
+		if file.IsChanged {
+			group.ChangedFiles++
+		}
+		group.Lines += file.Lines
+		group.Additions += file.Additions
+		group.Deletions += file.Deletions
+	}
+}
+
+// Groups returns the buckets sorted by line count, with shares of the totals
+// in stats, as GroupFiles does
+func (g *Grouper) Groups(stats *model.Stats) []*model.Group {
+	totalChanges := stats.TotalAdditions + stats.TotalDeletions
+
+	var labels map[string]string
+	if g.by.Kind == GroupByModule {
+		labels = ModuleLabels(stats.Modules)
+	}
+
+	result := make([]*model.Group, 0, len(g.groups))
+	for _, group := range g.groups {
+		group := *group
+		if label, ok := labels[group.Name]; ok {
+			group.Name = label
+		}
+		if stats.TotalLines > 0 {
+			group.LineShare = percent(group.Lines, stats.TotalLines)
+		}
+		if totalChanges > 0 {
+			group.ChangeShare = percent(group.Additions+group.Deletions, totalChanges)
+		}
+		result = append(result, &group)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Lines != result[j].Lines {
+			return result[i].Lines > result[j].Lines
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// percent returns part as a percentage of total, rounded to one decimal place
+func percent(part, total int) float64 {
+	return float64(int(float64(part)*1000/float64(total)+0.5)) / 10
+}
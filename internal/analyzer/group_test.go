@@ -0,0 +1,113 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+func TestParseGroupBy(t *testing.T) {
+	tests := []struct {
+		spec string
+		want GroupBy
+	}{
+		{"dir", GroupBy{Kind: GroupByDir, Depth: 1}},
+		{"dir:3", GroupBy{Kind: GroupByDir, Depth: 3}},
+		{" ext ", GroupBy{Kind: GroupByExt}},
+		{"lang", GroupBy{Kind: GroupByLang}},
+		{"module", GroupBy{Kind: GroupByModule}},
+		{"owner", GroupBy{Kind: GroupByOwner}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseGroupBy(tt.spec)
+		if err != nil {
+			t.Errorf("ParseGroupBy(%q): %v", tt.spec, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseGroupBy(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"", "dir:0", "dir:x", "ext:2", "size"} {
+		if _, err := ParseGroupBy(spec); err == nil {
+			t.Errorf("ParseGroupBy(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestGroupFiles(t *testing.T) {
+	stats := &model.Stats{
+		ChangedFiles: []*model.FileInfo{
+			{Path: "cmd/app/main.go", Lines: 50, Additions: 10, Deletions: 5, IsChanged: true, Module: ".", Owners: []string{"@core", "@cli"}},
+			{Path: "web/pkg/index.ts", Lines: 20, Additions: 5, IsChanged: true, Module: "web/pkg"},
+		},
+		UnchangedFiles: []*model.FileInfo{
+			{Path: "Makefile", Lines: 10, Module: "."},
+			{Path: "web/other/index.ts", Lines: 20, Module: "web/other"},
+		},
+		TotalLines:     100,
+		TotalAdditions: 15,
+		TotalDeletions: 5,
+		Modules: []*model.Module{
+			{Name: "example.com/app", Path: ".", Kind: ModuleGo},
+			{Name: "ui", Path: "web/pkg", Kind: ModuleNPM},
+			{Name: "ui", Path: "web/other", Kind: ModuleNPM},
+		},
+	}
+
+	tests := []struct {
+		by   GroupBy
+		want []model.Group
+	}{
+		{GroupBy{Kind: GroupByDir, Depth: 1}, []model.Group{
+			{Name: "cmd", Files: 1, ChangedFiles: 1, Lines: 50, Additions: 10, Deletions: 5, LineShare: 50, ChangeShare: 75},
+			{Name: "web", Files: 2, ChangedFiles: 1, Lines: 40, Additions: 5, LineShare: 40, ChangeShare: 25},
+			{Name: ".", Files: 1, Lines: 10, LineShare: 10},
+		}},
+		{GroupBy{Kind: GroupByExt}, []model.Group{
+			{Name: ".go", Files: 1, ChangedFiles: 1, Lines: 50, Additions: 10, Deletions: 5, LineShare: 50, ChangeShare: 75},
+			{Name: ".ts", Files: 2, ChangedFiles: 1, Lines: 40, Additions: 5, LineShare: 40, ChangeShare: 25},
+			{Name: "(none)", Files: 1, Lines: 10, LineShare: 10},
+		}},
+		// Modules sharing a name are told apart by their directory
+		{GroupBy{Kind: GroupByModule}, []model.Group{
+			{Name: "example.com/app", Files: 2, ChangedFiles: 1, Lines: 60, Additions: 10, Deletions: 5, LineShare: 60, ChangeShare: 75},
+			{Name: "ui (web/other)", Files: 1, Lines: 20, LineShare: 20},
+			{Name: "ui (web/pkg)", Files: 1, ChangedFiles: 1, Lines: 20, Additions: 5, LineShare: 20, ChangeShare: 25},
+		}},
+		// A file with several owners counts towards each of them
+		{GroupBy{Kind: GroupByOwner}, []model.Group{
+			{Name: NoOwner, Files: 3, ChangedFiles: 1, Lines: 50, Additions: 5, LineShare: 50, ChangeShare: 25},
+			{Name: "@cli", Files: 1, ChangedFiles: 1, Lines: 50, Additions: 10, Deletions: 5, LineShare: 50, ChangeShare: 75},
+			{Name: "@core", Files: 1, ChangedFiles: 1, Lines: 50, Additions: 10, Deletions: 5, LineShare: 50, ChangeShare: 75},
+		}},
+	}
+
+	for _, tt := range tests {
+		var got []model.Group
+		for _, g := range GroupFiles(stats, tt.by) {
+			got = append(got, *g)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GroupFiles by %s =\n%+v\nwant\n%+v", tt.by, got, tt.want)
+		}
+
+		// Streamed files arrive in any order
+		grouper := NewGrouper(tt.by)
+		for _, files := range [][]*model.FileInfo{stats.UnchangedFiles, stats.ChangedFiles} {
+			for i := len(files) - 1; i >= 0; i-- {
+				grouper.Add(files[i])
+			}
+		}
+		got = nil
+		for _, g := range grouper.Groups(stats) {
+			got = append(got, *g)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Grouper by %s =\n%+v\nwant\n%+v", tt.by, got, tt.want)
+		}
+	}
+}
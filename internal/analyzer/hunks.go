@@ -0,0 +1,162 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/nodelike/diffloc/internal/model"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// diffHunks computes the changed line ranges between two versions of a file,
+// like a unified diff without context lines
+func diffHunks(headContent, workContent string) []*model.Hunk {
+	var hunks []*model.Hunk
+	var hunk *model.Hunk
+	oldLine, newLine := 1, 1
+
+	for _, d := range diff.Do(headContent, workContent) {
+		n := contentLines(d.Text)
+
+		if d.Type == diffmatchpatch.DiffEqual {
+			if hunk != nil {
+				hunks = append(hunks, closeHunk(hunk))
+				hunk = nil
+			}
+			oldLine += n
+			newLine += n
+			continue
+		}
+
+		if hunk == nil {
+			hunk = &model.Hunk{OldStart: oldLine, NewStart: newLine}
+		}
+		for i := 0; i < n; i++ {
+			if d.Type == diffmatchpatch.DiffInsert {
+				hunk.Added = append(hunk.Added, newLine)
+				hunk.NewLines++
+				newLine++
+			} else {
+				hunk.Removed = append(hunk.Removed, oldLine)
+				hunk.OldLines++
+				oldLine++
+			}
+		}
+	}
+
+	if hunk != nil {
+		hunks = append(hunks, closeHunk(hunk))
+	}
+	return hunks
+}
+
+// hunkTotals returns the number of lines the hunks add and remove
+func hunkTotals(hunks []*model.Hunk) (additions, deletions int) {
+	for _, hunk := range hunks {
+		additions += hunk.NewLines
+		deletions += hunk.OldLines
+	}
+	return additions, deletions
+}
+
+// closeHunk points the start of an empty side at the line before the change,
+// as unified diffs do
+func closeHunk(hunk *model.Hunk) *model.Hunk {
+	if hunk.OldLines == 0 {
+		hunk.OldStart--
+	}
+	if hunk.NewLines == 0 {
+		hunk.NewStart--
+	}
+	return hunk
+}
+
+// rawLineNumbers maps each line of the gofmt-formatted version of a file to
+// its line in the raw source, at index line number. Unchanged lines map
+// directly; reformatted lines take the raw lines they replace, in order.
+func rawLineNumbers(raw, formatted string) []int {
+	last := max(contentLines(raw), 1)
+	numbers := []int{0}
+	rawLine, changeStart := 1, 1
+
+	for _, d := range diff.Do(raw, formatted) {
+		n := contentLines(d.Text)
+		switch d.Type {
+		case diffmatchpatch.DiffEqual:
+			for i := 0; i < n; i++ {
+				numbers = append(numbers, rawLine+i)
+			}
+			rawLine += n
+			changeStart = rawLine
+		case diffmatchpatch.DiffDelete:
+			rawLine += n
+		case diffmatchpatch.DiffInsert:
+			for i := 0; i < n; i++ {
+				numbers = append(numbers, min(changeStart+i, max(rawLine-1, changeStart), last))
+			}
+		}
+	}
+	return numbers
+}
+
+// renumberHunks rewrites hunks computed on formatted sources with the line
+// numbers of the raw sources, as given by rawLineNumbers for each side
+func renumberHunks(hunks []*model.Hunk, oldNumbers, newNumbers []int) {
+	for _, hunk := range hunks {
+		hunk.Removed = renumberLines(hunk.Removed, oldNumbers)
+		hunk.Added = renumberLines(hunk.Added, newNumbers)
+		hunk.OldStart, hunk.OldLines = renumberStart(hunk.OldStart, hunk.Removed, oldNumbers), len(hunk.Removed)
+		hunk.NewStart, hunk.NewLines = renumberStart(hunk.NewStart, hunk.Added, newNumbers), len(hunk.Added)
+	}
+}
+
+// renumberLines maps line numbers, dropping the duplicates left when several
+// formatted lines come from one raw line
+func renumberLines(lines, numbers []int) []int {
+	var renumbered []int
+	for _, line := range lines {
+		n := numbers[line]
+		if len(renumbered) == 0 || renumbered[len(renumbered)-1] != n {
+			renumbered = append(renumbered, n)
+		}
+	}
+	return renumbered
+}
+
+// renumberStart maps the start of one side of a hunk; an empty side keeps
+// pointing at the line before the change
+func renumberStart(start int, lines, numbers []int) int {
+	if len(lines) > 0 {
+		return lines[0]
+	}
+	if start == 0 {
+		return 0
+	}
+	return numbers[start]
+}
+
+// wholeFileHunk covers every line of an added or deleted file
+func wholeFileHunk(lines int, added bool) []*model.Hunk {
+	if lines == 0 {
+		return nil
+	}
+
+	numbers := make([]int, lines)
+	for i := range numbers {
+		numbers[i] = i + 1
+	}
+
+	if added {
+		return []*model.Hunk{{NewStart: 1, NewLines: lines, Added: numbers}}
+	}
+	return []*model.Hunk{{OldStart: 1, OldLines: lines, Removed: numbers}}
+}
+
+// contentLines counts the lines of a diff chunk, including a final line without a newline
+func contentLines(text string) int {
+	n := strings.Count(text, "\n")
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		n++
+	}
+	return n
+}
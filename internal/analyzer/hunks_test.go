@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+func TestDiffHunks(t *testing.T) {
+	tests := []struct {
+		name      string
+		head      string
+		work      string
+		want      []*model.Hunk
+		additions int
+		deletions int
+	}{
+		{
+			name: "unchanged",
+			head: "a\nb\n",
+			work: "a\nb\n",
+		},
+		{
+			name:      "modified line",
+			head:      "a\nb\nc\n",
+			work:      "a\nB\nc\n",
+			want:      []*model.Hunk{{OldStart: 2, OldLines: 1, NewStart: 2, NewLines: 1, Added: []int{2}, Removed: []int{2}}},
+			additions: 1,
+			deletions: 1,
+		},
+		{
+			name:      "insertion at the top",
+			head:      "b\n",
+			work:      "a\nb\n",
+			want:      []*model.Hunk{{OldStart: 0, NewStart: 1, NewLines: 1, Added: []int{1}}},
+			additions: 1,
+		},
+		{
+			name:      "deletion at the top",
+			head:      "a\nb\n",
+			work:      "b\n",
+			want:      []*model.Hunk{{OldStart: 1, OldLines: 1, NewStart: 0, Removed: []int{1}}},
+			deletions: 1,
+		},
+		{
+			name: "moved line",
+			head: "a\nb\nc\n",
+			work: "c\na\nb\n",
+			want: []*model.Hunk{
+				{OldStart: 0, NewStart: 1, NewLines: 1, Added: []int{1}},
+				{OldStart: 3, OldLines: 1, NewStart: 3, Removed: []int{3}},
+			},
+			additions: 1,
+			deletions: 1,
+		},
+		{
+			name:      "removed duplicate",
+			head:      "x\nx\ny\n",
+			work:      "x\ny\n",
+			want:      []*model.Hunk{{OldStart: 1, OldLines: 1, NewStart: 0, Removed: []int{1}}},
+			deletions: 1,
+		},
+		{
+			name:      "missing final newline",
+			head:      "a",
+			work:      "a\nb",
+			want:      []*model.Hunk{{OldStart: 1, OldLines: 1, NewStart: 1, NewLines: 2, Added: []int{1, 2}, Removed: []int{1}}},
+			additions: 2,
+			deletions: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hunks := diffHunks(tt.head, tt.work)
+			if !reflect.DeepEqual(hunks, tt.want) {
+				t.Errorf("diffHunks = %+v, want %+v", formatHunks(hunks), formatHunks(tt.want))
+			}
+
+			additions, deletions := hunkTotals(hunks)
+			if additions != tt.additions || deletions != tt.deletions {
+				t.Errorf("hunkTotals = +%d -%d, want +%d -%d", additions, deletions, tt.additions, tt.deletions)
+			}
+		})
+	}
+}
+
+func formatHunks(hunks []*model.Hunk) []model.Hunk {
+	var list []model.Hunk
+	for _, h := range hunks {
+		list = append(list, *h)
+	}
+	return list
+}
+
+func TestDiffModifiedIgnoreGoFormat(t *testing.T) {
+	head := "package a\n\nimport \"fmt\"\n\nfunc A() {\n\tfmt.Println(\"a\")\n}\n\nfunc B() {\n\tfmt.Println(\"b\")\n}\n"
+	// Two extra blank lines and a badly indented body are gofmt-only changes;
+	// the edit in B, on line 12, is the only real one
+	work := "package a\n\nimport \"fmt\"\n\n\n\nfunc A() {\nfmt.Println(\"a\")\n}\n\nfunc B() {\n\tfmt.Println(\"c\")\n}\n"
+
+	file := &model.FileInfo{Path: "a.go"}
+	diffModified(file, head, work, Options{IgnoreGoFormat: true, Hunks: true})
+
+	if file.Additions != 1 || file.Deletions != 1 {
+		t.Errorf("got +%d -%d, want +1 -1", file.Additions, file.Deletions)
+	}
+	if file.HeadLines != 11 {
+		t.Errorf("HeadLines = %d, want 11", file.HeadLines)
+	}
+	want := []model.Hunk{{OldStart: 10, OldLines: 1, NewStart: 12, NewLines: 1, Added: []int{12}, Removed: []int{10}}}
+	if got := formatHunks(file.Hunks); !reflect.DeepEqual(got, want) {
+		t.Errorf("hunks = %+v, want %+v", got, want)
+	}
+}
+
+func TestRawLineNumbers(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		formatted string
+		want      []int
+	}{
+		{"identical", "a\nb\n", "a\nb\n", []int{0, 1, 2}},
+		{"collapsed blank lines", "a\n\n\n\nb\n", "a\n\nb\n", []int{0, 1, 2, 5}},
+		{"reindented line", "a\n b\nc\n", "a\n\tb\nc\n", []int{0, 1, 2, 3}},
+		{"joined lines", "a\nb1\nb2\nc\n", "a\nb\nc\n", []int{0, 1, 2, 4}},
+		{"split line", "a\nb\nc\n", "a\nb1\nb2\nc\n", []int{0, 1, 2, 2, 3}},
+		{"inserted line", "a\nc\n", "a\nb\nc\n", []int{0, 1, 2, 2}},
+		{"final newline", "a\nb", "a\nb\n", []int{0, 1, 2}},
+	}
+
+	for _, tt := range tests {
+		if got := rawLineNumbers(tt.raw, tt.formatted); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
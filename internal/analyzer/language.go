@@ -0,0 +1,72 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+var languagesByExt = map[string]string{
+	".go":     "Go",
+	".py":     "Python",
+	".pyi":    "Python",
+	".js":     "JavaScript",
+	".jsx":    "JavaScript",
+	".mjs":    "JavaScript",
+	".cjs":    "JavaScript",
+	".ts":     "TypeScript",
+	".tsx":    "TypeScript",
+	".mts":    "TypeScript",
+	".cts":    "TypeScript",
+	".vue":    "Vue",
+	".svelte": "Svelte",
+	".rs":     "Rust",
+	".java":   "Java",
+	".kt":     "Kotlin",
+	".kts":    "Kotlin",
+	".scala":  "Scala",
+	".rb":     "Ruby",
+	".php":    "PHP",
+	".c":      "C",
+	".h":      "C",
+	".cc":     "C++",
+	".cpp":    "C++",
+	".cxx":    "C++",
+	".hpp":    "C++",
+	".cs":     "C#",
+	".swift":  "Swift",
+	".m":      "Objective-C",
+	".dart":   "Dart",
+	".ex":     "Elixir",
+	".exs":    "Elixir",
+	".erl":    "Erlang",
+	".hs":     "Haskell",
+	".lua":    "Lua",
+	".r":      "R",
+	".sh":     "Shell",
+	".bash":   "Shell",
+	".zsh":    "Shell",
+	".sql":    "SQL",
+	".html":   "HTML",
+	".css":    "CSS",
+	".scss":   "SCSS",
+	".proto":  "Protocol Buffers",
+	".tf":     "Terraform",
+	".yaml":   "YAML",
+	".yml":    "YAML",
+	".json":   "JSON",
+	".toml":   "TOML",
+	".md":     "Markdown",
+}
+
+// Language returns the language name for a file based on its extension.
+// Unknown extensions are reported as the bare extension, or "Other" if none.
+func Language(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if lang, ok := languagesByExt[ext]; ok {
+		return lang
+	}
+	if ext == "" {
+		return "Other"
+	}
+	return strings.TrimPrefix(ext, ".")
+}
@@ -0,0 +1,318 @@
+package analyzer
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+// Module kinds detected from manifest files
+const (
+	ModuleGo     = "go"
+	ModuleNPM    = "npm"
+	ModulePython = "python"
+)
+
+// NoModule is the bucket name for files outside every detected module
+const NoModule = "(none)"
+
+// ModuleSet resolves files to the module that owns them
+type ModuleSet struct {
+	modules []*model.Module // Sorted deepest directory first
+}
+
+// Owner returns the directory of the innermost module containing path, or "" if
+// none does. Directories identify modules, as names need not be unique.
+func (s *ModuleSet) Owner(path string) string {
+	if s == nil {
+		return ""
+	}
+
+	path = filepath.ToSlash(path)
+	for _, mod := range s.modules {
+		if mod.Path == "." || path == mod.Path || strings.HasPrefix(path, mod.Path+"/") {
+			return mod.Path
+		}
+	}
+	return ""
+}
+
+// ModuleLabels maps module directories to names for display. Names shared by
+// several modules are qualified with their directory.
+func ModuleLabels(modules []*model.Module) map[string]string {
+	count := make(map[string]int)
+	for _, mod := range modules {
+		count[mod.Name]++
+	}
+
+	labels := make(map[string]string, len(modules))
+	for _, mod := range modules {
+		labels[mod.Path] = mod.Name
+		if count[mod.Name] > 1 {
+			labels[mod.Path] = mod.Name + " (" + mod.Path + ")"
+		}
+	}
+	return labels
+}
+
+// List returns the detected modules sorted by directory
+func (s *ModuleSet) List() []*model.Module {
+	if s == nil {
+		return nil
+	}
+
+	list := make([]*model.Module, len(s.modules))
+	copy(list, s.modules)
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Path < list[j].Path
+	})
+	return list
+}
+
+// packageManifest is the subset of package.json needed for workspace detection
+type packageManifest struct {
+	Name       string          `json:"name"`
+	Workspaces json.RawMessage `json:"workspaces"`
+}
+
+// workspacePatterns returns the workspace globs of a package.json, which may be
+// either an array or an object with a "packages" array
+func (p packageManifest) workspacePatterns() []string {
+	if len(p.Workspaces) == 0 {
+		return nil
+	}
+
+	var patterns []string
+	if err := json.Unmarshal(p.Workspaces, &patterns); err == nil {
+		return patterns
+	}
+
+	var object struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(p.Workspaces, &object); err == nil {
+		return object.Packages
+	}
+	return nil
+}
+
+// DetectModules walks rootPath for go.mod, go.work, package.json workspaces and
+// pyproject.toml files and returns the module boundaries they define. Directories
+// excluded by the filter (node_modules, vendor, .gitignore'd paths, ...) are skipped.
+func DetectModules(rootPath string, filter *Filter) (*ModuleSet, error) {
+	byDir := make(map[string]*model.Module)
+	packages := make(map[string]packageManifest)
+	workspaceUses := make([]string, 0)
+
+	err := filepath.WalkDir(rootPath, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(rootPath, path)
+		if err != nil {
+			return nil
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if d.IsDir() {
+			if relPath != "." && filter.ExcludesDir(relPath) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		dir := filepath.ToSlash(filepath.Dir(relPath))
+
+		switch d.Name() {
+		case "go.mod":
+			if name := readGoModulePath(path); name != "" {
+				byDir[dir] = &model.Module{Name: name, Path: dir, Kind: ModuleGo}
+			}
+		case "go.work":
+			for _, use := range readGoWorkUses(path) {
+				workspaceUses = append(workspaceUses, filepath.ToSlash(filepath.Join(dir, use)))
+			}
+		case "package.json":
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			var manifest packageManifest
+			if json.Unmarshal(content, &manifest) == nil {
+				packages[dir] = manifest
+			}
+		case "pyproject.toml":
+			if _, ok := byDir[dir]; ok {
+				return nil
+			}
+			name := readPyprojectName(path)
+			if name == "" {
+				name = filepath.Base(filepath.Join(rootPath, dir))
+			}
+			byDir[dir] = &model.Module{Name: name, Path: dir, Kind: ModulePython}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range workspaceUses {
+		if _, ok := byDir[dir]; ok {
+			continue
+		}
+		if name := readGoModulePath(filepath.Join(rootPath, dir, "go.mod")); name != "" {
+			byDir[dir] = &model.Module{Name: name, Path: dir, Kind: ModuleGo}
+		}
+	}
+
+	workspaceGlobs := make([]glob.Glob, 0)
+	for dir, manifest := range packages {
+		for _, pattern := range manifest.workspacePatterns() {
+			pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "./"), "/")
+			if dir != "." {
+				pattern = dir + "/" + pattern
+			}
+			if g, err := glob.Compile(pattern, '/'); err == nil {
+				workspaceGlobs = append(workspaceGlobs, g)
+			}
+		}
+	}
+
+	for dir, manifest := range packages {
+		if _, ok := byDir[dir]; ok {
+			continue
+		}
+
+		isModule := dir == "." || len(manifest.workspacePatterns()) > 0
+		for _, g := range workspaceGlobs {
+			if g.Match(dir) {
+				isModule = true
+				break
+			}
+		}
+		if !isModule {
+			continue
+		}
+
+		name := manifest.Name
+		if name == "" {
+			name = filepath.Base(filepath.Join(rootPath, dir))
+		}
+		byDir[dir] = &model.Module{Name: name, Path: dir, Kind: ModuleNPM}
+	}
+
+	set := &ModuleSet{modules: make([]*model.Module, 0, len(byDir))}
+	for _, mod := range byDir {
+		set.modules = append(set.modules, mod)
+	}
+	sort.Slice(set.modules, func(i, j int) bool {
+		di, dj := moduleDepth(set.modules[i].Path), moduleDepth(set.modules[j].Path)
+		if di != dj {
+			return di > dj
+		}
+		return set.modules[i].Path < set.modules[j].Path
+	})
+
+	return set, nil
+}
+
+// moduleDepth returns the number of path components in a module directory
+func moduleDepth(dir string) int {
+	if dir == "." {
+		return 0
+	}
+	return strings.Count(dir, "/") + 1
+}
+
+// readGoModulePath returns the module path declared in a go.mod file
+func readGoModulePath(goModPath string) string {
+	file, err := os.Open(goModPath)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if idx := strings.Index(line, "//"); idx != -1 {
+			line = strings.TrimSpace(line[:idx])
+		}
+		if rest, ok := strings.CutPrefix(line, "module"); ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
+
+// readGoWorkUses returns the directories listed in the use directives of a go.work file
+func readGoWorkUses(goWorkPath string) []string {
+	file, err := os.Open(goWorkPath)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	uses := make([]string, 0)
+	inBlock := false
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if idx := strings.Index(line, "//"); idx != -1 {
+			line = strings.TrimSpace(line[:idx])
+		}
+
+		switch {
+		case line == "":
+			continue
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock:
+			uses = append(uses, strings.Trim(line, `"`))
+		case line == "use (":
+			inBlock = true
+		case strings.HasPrefix(line, "use "):
+			uses = append(uses, strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "use ")), `"`))
+		}
+	}
+	return uses
+}
+
+// readPyprojectName returns the project name from the [project] or
+// [tool.poetry] table of a pyproject.toml file
+func readPyprojectName(pyprojectPath string) string {
+	file, err := os.Open(pyprojectPath)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	section := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, "[") {
+			section = strings.Trim(line, "[] ")
+			continue
+		}
+		if section != "project" && section != "tool.poetry" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.TrimSpace(key) == "name" {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// AnyLanguage is the test pattern key that applies to files of every language
+const AnyLanguage = "*"
+
+// DefaultTestPatterns holds the regular expressions that mark a file as a
+// test, keyed by language name as returned by Language
+var DefaultTestPatterns = map[string][]string{
+	AnyLanguage:  {`(^|/)tests?($|/)`},
+	"Go":         {`_test\.go$`},
+	"Python":     {`(^|/)test_[^/]*\.py$`, `_test\.py$`, `(^|/)conftest\.py$`},
+	"JavaScript": {`\.(test|spec)\.(js|jsx|mjs|cjs)$`},
+	"TypeScript": {`\.(test|spec)\.(ts|tsx|mts|cts)$`},
+}
+
+// TestClassifier tells test files from production files
+type TestClassifier struct {
+	patterns map[string][]*regexp.Regexp // Keyed by lower-case language
+}
+
+// NewTestClassifier compiles the default test patterns with overrides
+// applied. Each language in overrides replaces the defaults of that language
+// (matched case-insensitively); an empty list means it has no test files.
+func NewTestClassifier(overrides map[string][]string) (*TestClassifier, error) {
+	specs := make(map[string][]string, len(DefaultTestPatterns)+len(overrides))
+	for lang, patterns := range DefaultTestPatterns {
+		specs[strings.ToLower(lang)] = patterns
+	}
+	for lang, patterns := range overrides {
+		specs[strings.ToLower(lang)] = patterns
+	}
+
+	c := &TestClassifier{patterns: make(map[string][]*regexp.Regexp, len(specs))}
+	for lang, patterns := range specs {
+		for _, pattern := range patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid test pattern %q for %s: %w", pattern, lang, err)
+			}
+			c.patterns[lang] = append(c.patterns[lang], re)
+		}
+	}
+	return c, nil
+}
+
+// IsTest reports whether path is a test file, or a directory holding tests when it ends in "/"
+func (c *TestClassifier) IsTest(path string) bool {
+	path = filepath.ToSlash(path)
+	for _, lang := range []string{AnyLanguage, strings.ToLower(Language(path))} {
+		for _, re := range c.patterns[lang] {
+			if re.MatchString(path) {
+				return true
+			}
+		}
+	}
+	return false
+}
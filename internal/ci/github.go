@@ -0,0 +1,158 @@
+package ci
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nodelike/diffloc/internal/report"
+)
+
+// Providers accepted by --ci
+const (
+	ProviderGitHub = "github"
+	ProviderNone   = "none"
+)
+
+// Detect returns the CI provider selected with --ci, falling back to the
+// environment when the flag is empty. Returns "" outside of CI.
+func Detect(provider string) (string, error) {
+	switch provider {
+	case ProviderGitHub:
+		return ProviderGitHub, nil
+	case ProviderNone:
+		return "", nil
+	case "":
+		if os.Getenv("GITHUB_ACTIONS") == "true" {
+			return ProviderGitHub, nil
+		}
+		return "", nil
+	default:
+		return "", fmt.Errorf("unknown --ci %q (expected github or none)", provider)
+	}
+}
+
+// GitHubOptions controls the GitHub Actions integration
+type GitHubOptions struct {
+	StepSummary  string    // File the Markdown summary is appended to ($GITHUB_STEP_SUMMARY)
+	Output       string    // File step outputs are appended to ($GITHUB_OUTPUT)
+	Annotations  io.Writer // Receives ::warning workflow commands
+	MaxLines     int       // Annotate changed files longer than this; 0 disables
+	MaxAdditions int       // Annotate files adding more lines than this; 0 disables
+	Top          int       // Number of files in the summary's top table
+}
+
+// GitHubOptionsFromEnv reads the step summary and output paths from the environment
+func GitHubOptionsFromEnv() GitHubOptions {
+	return GitHubOptions{
+		StepSummary: os.Getenv("GITHUB_STEP_SUMMARY"),
+		Output:      os.Getenv("GITHUB_OUTPUT"),
+	}
+}
+
+// RunGitHub writes the Markdown report to the step summary, annotates
+// oversized changed files and sets the additions, deletions, net_change,
+// changed_files, total_files and total_lines step outputs. Missing summary
+// or output paths are skipped.
+func RunGitHub(r *report.Report, opts GitHubOptions) error {
+	if opts.StepSummary != "" {
+		if err := appendToFile(opts.StepSummary, func(w io.Writer) error {
+			return report.WriteMarkdown(w, r, report.MarkdownOptions{Top: opts.Top})
+		}); err != nil {
+			return fmt.Errorf("writing step summary: %w", err)
+		}
+	}
+
+	if opts.Annotations != nil {
+		if err := writeAnnotations(opts.Annotations, r, opts); err != nil {
+			return fmt.Errorf("writing annotations: %w", err)
+		}
+	}
+
+	if opts.Output != "" {
+		if err := appendToFile(opts.Output, func(w io.Writer) error {
+			return writeStepOutputs(w, r.Summary)
+		}); err != nil {
+			return fmt.Errorf("writing step outputs: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeAnnotations emits a warning for every changed file above a size threshold
+func writeAnnotations(w io.Writer, r *report.Report, opts GitHubOptions) error {
+	for _, file := range r.Files {
+		if !file.Changed {
+			continue
+		}
+
+		if opts.MaxLines > 0 && file.Lines > opts.MaxLines {
+			message := fmt.Sprintf("%s has %d lines (threshold %d)", file.Path, file.Lines, opts.MaxLines)
+			if err := writeWarning(w, file.Path, "Large file", message); err != nil {
+				return err
+			}
+		}
+		if opts.MaxAdditions > 0 && file.Additions > opts.MaxAdditions {
+			message := fmt.Sprintf("%s adds %d lines (threshold %d)", file.Path, file.Additions, opts.MaxAdditions)
+			if err := writeWarning(w, file.Path, "Large change", message); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeWarning writes a ::warning workflow command attached to a file
+func writeWarning(w io.Writer, file, title, message string) error {
+	_, err := fmt.Fprintf(w, "::warning file=%s,title=%s::%s\n",
+		escapeProperty(file), escapeProperty(title), escapeData(message))
+	return err
+}
+
+// writeStepOutputs writes the summary totals as key=value step outputs
+func writeStepOutputs(w io.Writer, s report.Summary) error {
+	outputs := []struct {
+		name  string
+		value int
+	}{
+		{"additions", s.Additions},
+		{"deletions", s.Deletions},
+		{"net_change", s.NetChange},
+		{"changed_files", s.ChangedFiles},
+		{"total_files", s.TotalFiles},
+		{"total_lines", s.TotalLines},
+	}
+
+	for _, output := range outputs {
+		if _, err := fmt.Fprintf(w, "%s=%d\n", output.name, output.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendToFile opens path for appending, as GitHub expects for its command files
+func appendToFile(path string, write func(w io.Writer) error) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// escapeData escapes the message of a workflow command
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a property value of a workflow command
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,149 @@
+package ci
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nodelike/diffloc/internal/model"
+	"github.com/nodelike/diffloc/internal/report"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		flag    string
+		actions string
+		want    string
+	}{
+		{"github", "", ProviderGitHub},
+		{"github", "true", ProviderGitHub},
+		{"", "true", ProviderGitHub},
+		{"", "", ""},
+		{"", "false", ""},
+		{"none", "true", ""},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("GITHUB_ACTIONS", tt.actions)
+		got, err := Detect(tt.flag)
+		if err != nil {
+			t.Errorf("Detect(%q) with GITHUB_ACTIONS=%q: %v", tt.flag, tt.actions, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Detect(%q) with GITHUB_ACTIONS=%q = %q, want %q", tt.flag, tt.actions, got, tt.want)
+		}
+	}
+
+	if _, err := Detect("circleci"); err == nil {
+		t.Error("Detect(circleci) succeeded, want an error")
+	}
+}
+
+// testReport has a large file, a large change and a file name that needs escaping
+func testReport() *report.Report {
+	stats := &model.Stats{
+		ChangedFiles: []*model.FileInfo{
+			{Path: "cmd/app/main.go", Lines: 1200, Additions: 40, Deletions: 5, IsChanged: true, Status: model.StatusModified},
+			{Path: "docs/a,b:c.go", Lines: 300, Additions: 300, IsChanged: true, Status: model.StatusAdded},
+		},
+		UnchangedFiles: []*model.FileInfo{
+			{Path: "internal/big.go", Lines: 5000, Status: model.StatusUnchanged},
+		},
+		TotalFiles:     3,
+		ChangedCount:   2,
+		UnchangedCount: 1,
+		TotalLines:     6500,
+		TotalAdditions: 340,
+		TotalDeletions: 5,
+		NetChange:      335,
+		ProdLines:      6500,
+		ProdAdditions:  340,
+	}
+	return report.New(stats, report.Metadata{Git: true, BaseRef: "HEAD"})
+}
+
+func TestRunGitHub(t *testing.T) {
+	dir := t.TempDir()
+	summary := filepath.Join(dir, "summary.md")
+	output := filepath.Join(dir, "output")
+	if err := os.WriteFile(summary, []byte("# Earlier step\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_STEP_SUMMARY", summary)
+	t.Setenv("GITHUB_OUTPUT", output)
+
+	var annotations bytes.Buffer
+	opts := GitHubOptionsFromEnv()
+	opts.Annotations = &annotations
+	opts.MaxLines = 1000
+	opts.MaxAdditions = 100
+	opts.Top = 1
+
+	if err := RunGitHub(testReport(), opts); err != nil {
+		t.Fatalf("RunGitHub: %v", err)
+	}
+
+	content, err := os.ReadFile(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	markdown := string(content)
+	if !strings.HasPrefix(markdown, "# Earlier step\n") {
+		t.Errorf("step summary was not appended to:\n%s", markdown)
+	}
+	for _, want := range []string{
+		"### 📊 diffloc report",
+		"| Files changed | 2 of 3 |",
+		"| Lines added | +340 |",
+		"| Net change | +335 |",
+		"#### Top 1 changed files",
+		"| `docs/a,b:c.go` | +300 | -0 |",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("step summary is missing %q:\n%s", want, markdown)
+		}
+	}
+
+	content, err = os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantOutput := "additions=340\ndeletions=5\nnet_change=335\nchanged_files=2\ntotal_files=3\ntotal_lines=6500\n"
+	if string(content) != wantOutput {
+		t.Errorf("step outputs = %q, want %q", content, wantOutput)
+	}
+
+	// Unchanged files are never annotated, however large
+	wantAnnotations := "::warning file=cmd/app/main.go,title=Large file::cmd/app/main.go has 1200 lines (threshold 1000)\n" +
+		"::warning file=docs/a%2Cb%3Ac.go,title=Large change::docs/a,b:c.go adds 300 lines (threshold 100)\n"
+	if got := annotations.String(); got != wantAnnotations {
+		t.Errorf("annotations = %q, want %q", got, wantAnnotations)
+	}
+}
+
+func TestRunGitHubWithoutFiles(t *testing.T) {
+	t.Setenv("GITHUB_STEP_SUMMARY", "")
+	t.Setenv("GITHUB_OUTPUT", "")
+
+	var annotations bytes.Buffer
+	opts := GitHubOptionsFromEnv()
+	opts.Annotations = &annotations
+	if err := RunGitHub(testReport(), opts); err != nil {
+		t.Fatalf("RunGitHub: %v", err)
+	}
+	if annotations.Len() > 0 {
+		t.Errorf("annotations without thresholds: %q", annotations.String())
+	}
+}
+
+func TestEscape(t *testing.T) {
+	if got, want := escapeData("50% done\r\nnext: a,b"), "50%25 done%0D%0Anext: a,b"; got != want {
+		t.Errorf("escapeData = %q, want %q", got, want)
+	}
+	if got, want := escapeProperty("a:b,c%\n"), "a%3Ab%2Cc%25%0A"; got != want {
+		t.Errorf("escapeProperty = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,69 @@
+package coverage
+
+import (
+	"encoding/xml"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// coberturaReport is the subset of a Cobertura XML report holding line hits
+type coberturaReport struct {
+	Sources  []string `xml:"sources>source"`
+	Packages []struct {
+		Classes []struct {
+			Filename string `xml:"filename,attr"`
+			Lines    []struct {
+				Number int   `xml:"number,attr"`
+				Hits   int64 `xml:"hits,attr"`
+			} `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+// readCobertura reads a Cobertura XML report, as written by coverage.py
+// (coverage xml), pytest-cov, Istanbul and most JVM and .NET tools. Class
+// file names are relative to one of the report's sources.
+func (p *Profile) readCobertura(r io.Reader, opts Options) error {
+	var report coberturaReport
+	if err := xml.NewDecoder(r).Decode(&report); err != nil {
+		return err
+	}
+
+	resolved := make(map[string]string)
+	for _, pkg := range report.Packages {
+		for _, class := range pkg.Classes {
+			file, ok := resolved[class.Filename]
+			if !ok {
+				file = resolveCoberturaPath(class.Filename, report.Sources, opts)
+				resolved[class.Filename] = file
+			}
+
+			for _, line := range class.Lines {
+				p.add(file, line.Number, line.Hits > 0)
+			}
+		}
+	}
+	return nil
+}
+
+// resolveCoberturaPath maps a class file name onto a repository path. The
+// first source that places the file inside the repository wins; without
+// one, the file name is resolved on its own.
+func resolveCoberturaPath(filename string, sources []string, opts Options) string {
+	for _, source := range sources {
+		candidate := opts.resolve(path.Join(filepath.ToSlash(strings.TrimSpace(source)), filepath.ToSlash(filename)))
+		if path.IsAbs(candidate) || candidate == ".." || strings.HasPrefix(candidate, "../") {
+			continue
+		}
+		if opts.Root == "" {
+			return candidate
+		}
+		if _, err := os.Stat(filepath.Join(opts.Root, filepath.FromSlash(candidate))); err == nil {
+			return candidate
+		}
+	}
+	return opts.resolve(filename)
+}
@@ -0,0 +1,76 @@
+package coverage
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadCobertura(t *testing.T) {
+	const report = `<?xml version="1.0" ?>
+<coverage version="7.4.0" line-rate="0.5">
+	<sources>
+		<source>/ci/build/src</source>
+	</sources>
+	<packages>
+		<package name="app">
+			<classes>
+				<class name="main.py" filename="app/main.py">
+					<lines>
+						<line number="1" hits="1"/>
+						<line number="2" hits="0"/>
+					</lines>
+				</class>
+				<class name="main.py" filename="app/main.py">
+					<lines>
+						<line number="2" hits="4"/>
+						<line number="5" hits="0"/>
+					</lines>
+				</class>
+			</classes>
+		</package>
+	</packages>
+</coverage>`
+
+	p := newProfile()
+	if err := p.readCobertura(strings.NewReader(report), Options{StripPrefix: "/ci/build/"}); err != nil {
+		t.Fatalf("readCobertura: %v", err)
+	}
+
+	assertLines(t, p, "src/app/main.py", map[int]bool{1: true, 2: true, 5: false})
+}
+
+func TestReadCoberturaSources(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "lib", "pkg", "mod.py"), "x = 1\n")
+
+	// The first source lies outside the repository and the second has no
+	// pkg/mod.py, so the third one wins
+	report := fmt.Sprintf(`<coverage>
+	<sources>
+		<source>/elsewhere</source>
+		<source>%s</source>
+		<source>%s</source>
+	</sources>
+	<packages><package><classes>
+		<class filename="pkg/mod.py"><lines><line number="1" hits="1"/></lines></class>
+		<class filename="other.py"><lines><line number="3" hits="0"/></lines></class>
+	</classes></package></packages>
+</coverage>`, filepath.ToSlash(root), filepath.ToSlash(filepath.Join(root, "lib")))
+
+	p := newProfile()
+	if err := p.readCobertura(strings.NewReader(report), Options{Root: root}); err != nil {
+		t.Fatalf("readCobertura: %v", err)
+	}
+
+	assertLines(t, p, "lib/pkg/mod.py", map[int]bool{1: true})
+	// Files found below no source keep their own name
+	assertLines(t, p, "other.py", map[int]bool{3: false})
+}
+
+func TestReadCoberturaInvalid(t *testing.T) {
+	if err := newProfile().readCobertura(strings.NewReader("<coverage><packages>"), Options{}); err == nil {
+		t.Fatal("readCobertura succeeded on truncated XML, want an error")
+	}
+}
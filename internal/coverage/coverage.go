@@ -0,0 +1,122 @@
+package coverage
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+// Report formats accepted by Load
+const (
+	FormatGo        = "go"
+	FormatLCOV      = "lcov"
+	FormatCobertura = "cobertura"
+)
+
+// Options controls how the file names of a report are mapped onto the
+// repository-relative paths of model.FileInfo
+type Options struct {
+	Root        string          // Absolute repository root; absolute report paths below it are made relative
+	StripPrefix string          // Removed from report paths first, e.g. "/app/" for reports written in a container
+	Modules     []*model.Module // Go modules, to map the import paths of Go profiles
+}
+
+// Profile holds line coverage by repository-relative, slash-separated path
+type Profile struct {
+	files map[string]map[int]bool
+}
+
+func newProfile() *Profile {
+	return &Profile{files: make(map[string]map[int]bool)}
+}
+
+// Load reads coverage reports in any supported format, detected from their
+// content, and merges them into a single profile
+func Load(files []string, opts Options) (*Profile, error) {
+	profile := newProfile()
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		format, err := DetectFormat(content)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		switch format {
+		case FormatGo:
+			err = profile.readGo(bytes.NewReader(content), opts)
+		case FormatLCOV:
+			err = profile.readLCOV(bytes.NewReader(content), opts)
+		case FormatCobertura:
+			err = profile.readCobertura(bytes.NewReader(content), opts)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return profile, nil
+}
+
+// DetectFormat tells Go profiles, LCOV tracefiles and Cobertura XML reports apart
+func DetectFormat(content []byte) (string, error) {
+	trimmed := bytes.TrimSpace(content)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("mode:")):
+		return FormatGo, nil
+	case bytes.HasPrefix(trimmed, []byte("<")) && bytes.Contains(trimmed, []byte("<coverage")):
+		return FormatCobertura, nil
+	case bytes.HasPrefix(trimmed, []byte("TN:")) || bytes.HasPrefix(trimmed, []byte("SF:")):
+		return FormatLCOV, nil
+	}
+	return "", fmt.Errorf("unrecognized coverage report (expected a Go coverage profile, LCOV or Cobertura XML)")
+}
+
+// Lines maps the executable lines of a repository file to whether they are covered
+func (p *Profile) Lines(file string) (map[int]bool, bool) {
+	lines, ok := p.files[filepath.ToSlash(file)]
+	return lines, ok
+}
+
+// add records the coverage of a line. A line stays covered once any block
+// or report entry covers it.
+func (p *Profile) add(file string, line int, covered bool) {
+	lines := p.files[file]
+	if lines == nil {
+		lines = make(map[int]bool)
+		p.files[file] = lines
+	}
+	lines[line] = lines[line] || covered
+}
+
+// resolve maps a report file name onto a repository path: the strip prefix
+// is removed, and absolute paths below the root are made relative to it
+func (o Options) resolve(name string) string {
+	name = filepath.ToSlash(name)
+	if o.StripPrefix != "" {
+		if rest, ok := strings.CutPrefix(name, filepath.ToSlash(o.StripPrefix)); ok {
+			name = strings.TrimPrefix(rest, "/")
+		}
+	}
+
+	if o.Root != "" && path.IsAbs(name) {
+		root := strings.TrimSuffix(filepath.ToSlash(o.Root), "/")
+		if rest, ok := strings.CutPrefix(name, root+"/"); ok {
+			name = rest
+		}
+	}
+	return cleanPath(name)
+}
+
+// cleanPath normalizes a path to the slash-separated form used by model.FileInfo
+func cleanPath(name string) string {
+	name = path.Clean(filepath.ToSlash(name))
+	return strings.TrimPrefix(name, "./")
+}
@@ -0,0 +1,112 @@
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"mode: set\nexample.com/app/a.go:1.1,2.2 1 1\n", FormatGo},
+		{"\n  mode: atomic\n", FormatGo},
+		{"TN:\nSF:src/a.js\nDA:1,1\nend_of_record\n", FormatLCOV},
+		{"SF:src/a.js\nDA:1,1\nend_of_record\n", FormatLCOV},
+		{`<?xml version="1.0" ?><coverage version="7.4"></coverage>`, FormatCobertura},
+		{`<coverage line-rate="1"></coverage>`, FormatCobertura},
+	}
+
+	for _, tt := range tests {
+		got, err := DetectFormat([]byte(tt.content))
+		if err != nil {
+			t.Errorf("DetectFormat(%q): %v", tt.content, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("DetectFormat(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+
+	for _, content := range []string{"", "{}", "<html></html>", "package main\n"} {
+		if _, err := DetectFormat([]byte(content)); err == nil {
+			t.Errorf("DetectFormat(%q) succeeded, want an error", content)
+		}
+	}
+}
+
+func TestOptionsResolve(t *testing.T) {
+	tests := []struct {
+		opts Options
+		name string
+		want string
+	}{
+		{Options{}, "src/a.js", "src/a.js"},
+		{Options{}, "./src/../lib/a.js", "lib/a.js"},
+		{Options{Root: "/work/repo"}, "/work/repo/src/a.js", "src/a.js"},
+		{Options{Root: "/work/repo/"}, "/work/repo/src/a.js", "src/a.js"},
+		{Options{Root: "/work/repo"}, "/work/repository/a.js", "/work/repository/a.js"},
+		{Options{Root: "/work/repo"}, "/elsewhere/a.js", "/elsewhere/a.js"},
+		{Options{StripPrefix: "/app/"}, "/app/src/a.js", "src/a.js"},
+		{Options{StripPrefix: "/app"}, "/app/src/a.js", "src/a.js"},
+		{Options{StripPrefix: "/app/"}, "/other/src/a.js", "/other/src/a.js"},
+		{Options{Root: "/work/repo", StripPrefix: "/app/"}, "/app/src/a.js", "src/a.js"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.opts.resolve(tt.name); got != tt.want {
+			t.Errorf("%+v.resolve(%q) = %q, want %q", tt.opts, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoadMergesReports(t *testing.T) {
+	dir := t.TempDir()
+	lcov := filepath.Join(dir, "lcov.info")
+	cobertura := filepath.Join(dir, "coverage.xml")
+	writeFile(t, lcov, "SF:src/a.js\nDA:1,0\nDA:2,0\nend_of_record\n")
+	writeFile(t, cobertura, `<coverage><packages><package><classes>
+<class filename="src/a.js"><lines><line number="2" hits="3"/><line number="3" hits="0"/></lines></class>
+</classes></package></packages></coverage>`)
+
+	profile, err := Load([]string{lcov, cobertura}, Options{})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	// A line covered by any report stays covered
+	assertLines(t, profile, "src/a.js", map[int]bool{1: false, 2: true, 3: false})
+}
+
+func TestLoadRejectsUnknownFormat(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "coverage.json")
+	writeFile(t, file, "{}")
+
+	if _, err := Load([]string{file}, Options{}); err == nil {
+		t.Fatal("Load succeeded, want an error")
+	}
+}
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertLines(t *testing.T, p *Profile, file string, want map[int]bool) {
+	t.Helper()
+	got, ok := p.Lines(file)
+	if !ok {
+		t.Fatalf("no coverage for %s; have %v", file, p.files)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("coverage of %s = %v, want %v", file, got, want)
+	}
+}
@@ -0,0 +1,173 @@
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nodelike/diffloc/internal/analyzer"
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+// readGo reads a Go coverage profile as written by go test -coverprofile.
+// Profiles name files by import path, which is mapped to a repository path
+// through the Go modules in opts. Concatenated profiles, each with its own
+// mode line, are accepted.
+func (p *Profile) readGo(r io.Reader, opts Options) error {
+	resolve := goPathResolver(opts)
+	sources := make(map[string]map[int]bool)
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+
+		block, err := parseGoBlock(line)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if block.statements == 0 {
+			continue
+		}
+
+		file := resolve(block.file)
+		code, ok := sources[file]
+		if !ok {
+			code = goCodeLines(filepath.Join(opts.Root, filepath.FromSlash(file)))
+			sources[file] = code
+		}
+		for l := block.startLine; l <= block.endLine; l++ {
+			// Blank and comment-only lines inside a block are not executable
+			if code == nil || code[l] {
+				p.add(file, l, block.count > 0)
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// goCodeLines returns the lines of a Go source file that hold at least one
+// token other than a comment, or nil when the file cannot be read
+func goCodeLines(name string) map[int]bool {
+	src, err := os.ReadFile(name)
+	if err != nil {
+		return nil
+	}
+
+	fset := token.NewFileSet()
+	file := fset.AddFile(name, -1, len(src))
+
+	var s scanner.Scanner
+	s.Init(file, src, nil, 0)
+
+	lines := make(map[int]bool)
+	for {
+		pos, tok, _ := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		lines[file.Line(pos)] = true
+	}
+	return lines
+}
+
+// goBlock is one line of a Go coverage profile:
+// name.go:startLine.startCol,endLine.endCol statements count
+type goBlock struct {
+	file       string
+	startLine  int
+	endLine    int
+	statements int
+	count      int
+}
+
+func parseGoBlock(line string) (goBlock, error) {
+	var b goBlock
+
+	colon := strings.LastIndex(line, ":")
+	if colon < 0 {
+		return b, fmt.Errorf("invalid profile line %q", line)
+	}
+	b.file = line[:colon]
+
+	fields := strings.Fields(line[colon+1:])
+	if len(fields) != 3 {
+		return b, fmt.Errorf("invalid profile line %q", line)
+	}
+
+	start, end, ok := strings.Cut(fields[0], ",")
+	if !ok {
+		return b, fmt.Errorf("invalid block range %q", fields[0])
+	}
+
+	var err error
+	if b.startLine, _, err = blockPosition(start); err != nil {
+		return b, err
+	}
+	endCol := 0
+	if b.endLine, endCol, err = blockPosition(end); err != nil {
+		return b, err
+	}
+	// A block ending in column 1 stops before any code on its last line
+	if endCol <= 1 && b.endLine > b.startLine {
+		b.endLine--
+	}
+	if b.statements, err = strconv.Atoi(fields[1]); err != nil {
+		return b, fmt.Errorf("invalid statement count %q", fields[1])
+	}
+	if b.count, err = strconv.Atoi(fields[2]); err != nil {
+		return b, fmt.Errorf("invalid hit count %q", fields[2])
+	}
+	return b, nil
+}
+
+// blockPosition parses a "line.column" block position
+func blockPosition(pos string) (line, col int, err error) {
+	l, c, ok := strings.Cut(pos, ".")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid block position %q", pos)
+	}
+	if line, err = strconv.Atoi(l); err != nil {
+		return 0, 0, fmt.Errorf("invalid block position %q", pos)
+	}
+	if col, err = strconv.Atoi(c); err != nil {
+		return 0, 0, fmt.Errorf("invalid block position %q", pos)
+	}
+	return line, col, nil
+}
+
+// goPathResolver maps the import-path file names of a Go profile to
+// repository paths using the longest matching module path. Names outside
+// every module are resolved like the paths of other formats.
+func goPathResolver(opts Options) func(name string) string {
+	var goModules []*model.Module
+	for _, mod := range opts.Modules {
+		if mod.Kind == analyzer.ModuleGo {
+			goModules = append(goModules, mod)
+		}
+	}
+
+	return func(name string) string {
+		var best *model.Module
+		for _, mod := range goModules {
+			if strings.HasPrefix(name, mod.Name+"/") && (best == nil || len(mod.Name) > len(best.Name)) {
+				best = mod
+			}
+		}
+		if best == nil {
+			return opts.resolve(name)
+		}
+		return cleanPath(path.Join(best.Path, strings.TrimPrefix(name, best.Name+"/")))
+	}
+}
@@ -0,0 +1,100 @@
+package coverage
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nodelike/diffloc/internal/analyzer"
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+func TestReadGo(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "services", "app", "calc", "calc.go"), `package calc
+
+func Add(a, b int) int {
+	// Blank and comment lines inside a block are not executable
+
+	return a + b
+}
+
+func Sub(a, b int) int {
+	return a - b
+}
+`)
+
+	const profile = `mode: set
+example.com/app/calc/calc.go:3.24,6.14 1 1
+example.com/app/calc/calc.go:9.24,11.2 1 0
+example.com/app/calc/calc.go:9.24,11.2 0 1
+example.com/app/tools/gen/gen.go:1.1,4.1 2 0
+example.com/app/tools/gen/gen.go:2.5,2.30 1 1
+mode: set
+example.com/other/x.go:1.1,1.10 1 1
+`
+
+	modules := []*model.Module{
+		{Name: "example.com/app", Path: "services/app", Kind: analyzer.ModuleGo},
+		{Name: "example.com/app/tools", Path: "tools", Kind: analyzer.ModuleGo},
+		{Name: "example.com/app/web", Path: "web", Kind: analyzer.ModuleNPM},
+	}
+
+	p := newProfile()
+	if err := p.readGo(strings.NewReader(profile), Options{Root: root, Modules: modules}); err != nil {
+		t.Fatalf("readGo: %v", err)
+	}
+
+	// Lines 4 and 5 are a comment and a blank line; blocks without
+	// statements are ignored
+	assertLines(t, p, "services/app/calc/calc.go", map[int]bool{3: true, 6: true, 9: false, 10: false, 11: false})
+	// The longest module path wins; the source is not on disk, so every line
+	// of a block counts, and a block ending in column 1 stops on the line before
+	assertLines(t, p, "tools/gen/gen.go", map[int]bool{1: false, 2: true, 3: false})
+	// Import paths outside every module are resolved as they are
+	assertLines(t, p, "example.com/other/x.go", map[int]bool{1: true})
+}
+
+func TestParseGoBlock(t *testing.T) {
+	tests := []struct {
+		line string
+		want goBlock
+	}{
+		{"example.com/a/a.go:3.24,6.14 2 5", goBlock{"example.com/a/a.go", 3, 6, 2, 5}},
+		{"example.com/a/a.go:3.24,6.1 2 0", goBlock{"example.com/a/a.go", 3, 5, 2, 0}},
+		{"example.com/a/a.go:3.1,3.1 1 1", goBlock{"example.com/a/a.go", 3, 3, 1, 1}},
+		{`C:\src\a.go:1.1,2.5 1 1`, goBlock{`C:\src\a.go`, 1, 2, 1, 1}},
+	}
+
+	for _, tt := range tests {
+		got, err := parseGoBlock(tt.line)
+		if err != nil {
+			t.Errorf("parseGoBlock(%q): %v", tt.line, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseGoBlock(%q) = %+v, want %+v", tt.line, got, tt.want)
+		}
+	}
+
+	for _, line := range []string{
+		"a.go 1 1",
+		"a.go:3.24,6.14 1",
+		"a.go:3.24-6.14 1 1",
+		"a.go:3,6.14 1 1",
+		"a.go:x.1,6.14 1 1",
+		"a.go:3.24,6.14 one 1",
+		"a.go:3.24,6.14 1 one",
+	} {
+		if _, err := parseGoBlock(line); err == nil {
+			t.Errorf("parseGoBlock(%q) succeeded, want an error", line)
+		}
+	}
+}
+
+func TestReadGoReportsLine(t *testing.T) {
+	err := newProfile().readGo(strings.NewReader("mode: set\na.go:1.1,2.2 1 1\nbroken\n"), Options{})
+	if err == nil || !strings.HasPrefix(err.Error(), "line 3:") {
+		t.Fatalf("readGo error = %v, want one for line 3", err)
+	}
+}
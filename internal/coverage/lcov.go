@@ -0,0 +1,49 @@
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// readLCOV reads an LCOV tracefile (lcov.info), as written by Istanbul/nyc,
+// c8, Jest and genhtml's lcov. Only the SF (source file) and DA (line hits)
+// records are used.
+func (p *Profile) readLCOV(r io.Reader, opts Options) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	file := ""
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			file = opts.resolve(strings.TrimPrefix(line, "SF:"))
+		case line == "end_of_record":
+			file = ""
+		case strings.HasPrefix(line, "DA:"):
+			if file == "" {
+				return fmt.Errorf("line %d: DA record outside of a source file", lineNo)
+			}
+			fields := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+			if len(fields) < 2 {
+				return fmt.Errorf("line %d: invalid DA record %q", lineNo, line)
+			}
+			number, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return fmt.Errorf("line %d: invalid line number %q", lineNo, fields[0])
+			}
+			hits, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("line %d: invalid hit count %q", lineNo, fields[1])
+			}
+			p.add(file, number, hits > 0)
+		}
+	}
+	return scanner.Err()
+}
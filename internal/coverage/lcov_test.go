@@ -0,0 +1,46 @@
+package coverage
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadLCOV(t *testing.T) {
+	const tracefile = `TN:
+SF:/work/repo/src/a.js
+FN:1,main
+FNDA:1,main
+DA:1,1
+DA:2,0
+DA:4,12
+LF:3
+LH:2
+end_of_record
+SF:/app/lib/b.js
+DA:7,0
+end_of_record
+`
+
+	p := newProfile()
+	if err := p.readLCOV(strings.NewReader(tracefile), Options{Root: "/work/repo", StripPrefix: "/app/"}); err != nil {
+		t.Fatalf("readLCOV: %v", err)
+	}
+
+	assertLines(t, p, "src/a.js", map[int]bool{1: true, 2: false, 4: true})
+	assertLines(t, p, "lib/b.js", map[int]bool{7: false})
+}
+
+func TestReadLCOVErrors(t *testing.T) {
+	tests := []string{
+		"DA:1,1\n",
+		"SF:a.js\nDA:1\nend_of_record\n",
+		"SF:a.js\nDA:x,1\nend_of_record\n",
+		"SF:a.js\nDA:1,many\nend_of_record\n",
+	}
+
+	for _, tracefile := range tests {
+		if err := newProfile().readLCOV(strings.NewReader(tracefile), Options{}); err == nil {
+			t.Errorf("readLCOV(%q) succeeded, want an error", tracefile)
+		}
+	}
+}
@@ -4,9 +4,81 @@ package model
 type FileInfo struct {
 	Path      string
 	Lines     int
+	HeadLines int `json:",omitempty"` // Lines at HEAD of modified and deleted files
 	Additions int
 	Deletions int
 	IsChanged bool
+	IsTest    bool            `json:",omitempty"`
+	Status    string          `json:",omitempty"`
+	Symbols   []*SymbolChange `json:",omitempty"`
+
+	Complexity      int `json:",omitempty"`
+	ComplexityDelta int `json:",omitempty"`
+
+	Module string   `json:",omitempty"` // Directory of the owning module, as in Module.Path
+	Owners []string `json:",omitempty"`
+
+	Hunks    []*Hunk   `json:",omitempty"`
+	Coverage *Coverage `json:",omitempty"`
+}
+
+// Hunk is a run of changed lines, like a unified diff hunk without context
+type Hunk struct {
+	OldStart int // First removed line at HEAD, or the line after which lines were added
+	OldLines int
+	NewStart int // First added line in the working tree, or the line after which lines were removed
+	NewLines int
+	Added    []int // Line numbers in the working tree
+	Removed  []int // Line numbers at HEAD
+}
+
+// Coverage is the test coverage of the executable lines a change adds
+type Coverage struct {
+	Executable int
+	Covered    int
+	Uncovered  []int `json:",omitempty"` // Line numbers, empty in totals
+}
+
+// Percent returns the covered share of executable lines, or 100 if there are none
+func (c *Coverage) Percent() float64 {
+	if c.Executable == 0 {
+		return 100
+	}
+	return float64(c.Covered) * 100 / float64(c.Executable)
+}
+
+// File statuses relative to HEAD
+const (
+	StatusAdded     = "added"
+	StatusModified  = "modified"
+	StatusDeleted   = "deleted"
+	StatusUnchanged = "unchanged"
+)
+
+// Symbol kinds reported for Go declarations
+const (
+	SymbolFunc   = "func"
+	SymbolMethod = "method"
+	SymbolType   = "type"
+	SymbolVar    = "var"
+	SymbolConst  = "const"
+)
+
+// Symbol change types
+const (
+	SymbolAdded    = "added"
+	SymbolRemoved  = "removed"
+	SymbolModified = "modified"
+)
+
+// SymbolChange represents a top-level Go declaration touched by a change
+type SymbolChange struct {
+	Package   string
+	Kind      string
+	Name      string
+	Change    string
+	Additions int
+	Deletions int
 }
 
 // Stats represents aggregated statistics
@@ -20,6 +92,46 @@ type Stats struct {
 	TotalAdditions int
 	TotalDeletions int
 	NetChange      int
+
+	// Lines and additions split between test and production files
+	TestLines     int
+	TestAdditions int
+	ProdLines     int
+	ProdAdditions int
+
+	// Coverage of the changed lines, when a coverage report was given
+	Coverage *Coverage `json:",omitempty"`
+
+	TotalComplexity int `json:",omitempty"`
+	ComplexityDelta int `json:",omitempty"`
+
+	GroupBy string   `json:",omitempty"`
+	Groups  []*Group `json:",omitempty"`
+
+	Modules []*Module `json:",omitempty"`
+
+	Violations []*Violation `json:",omitempty"`
+}
+
+// Module represents a module boundary detected from a manifest file
+// (go.mod, package.json workspace or pyproject.toml)
+type Module struct {
+	Name string
+	Path string // Directory relative to the analyzed root, "." for the root itself
+	Kind string
+}
+
+// Group represents aggregated statistics for a bucket of files
+// (a directory, extension or language)
+type Group struct {
+	Name         string
+	Files        int
+	ChangedFiles int
+	Lines        int
+	Additions    int
+	Deletions    int
+	LineShare    float64 // Percentage of total lines
+	ChangeShare  float64 // Percentage of total additions and deletions
 }
 
 // SortMode defines how files should be sorted
@@ -47,3 +159,51 @@ func (s SortMode) String() string {
 	}
 }
 
+// Violation represents a size budget or policy rule broken by the analyzed files
+type Violation struct {
+	Rule    string // Rule ID, e.g. "max-additions"
+	Scope   string // Path pattern of a per-path budget, "other-files", or empty for the whole change
+	Path    string // File the violation refers to, empty for aggregate budgets
+	Message string
+	Limit   int
+	Actual  int
+}
+
+// Delta pairs a count from an older analysis with the same count from a newer one
+type Delta struct {
+	Old int
+	New int
+}
+
+// Change returns the difference between the new and the old count
+func (d Delta) Change() int {
+	return d.New - d.Old
+}
+
+// FileDelta is a file that was added, deleted or resized between two analyses.
+// Status is StatusAdded, StatusDeleted or StatusModified.
+type FileDelta struct {
+	Path   string
+	Status string
+	Lines  Delta
+}
+
+// GroupDelta compares the files and lines of a bucket (a directory or
+// language, or the whole tree) between two analyses
+type GroupDelta struct {
+	Name  string
+	Files Delta
+	Lines Delta
+}
+
+// Comparison describes how a codebase changed between two analyses
+type Comparison struct {
+	Total          GroupDelta
+	AddedFiles     int
+	DeletedFiles   int
+	ModifiedFiles  int // Files in both analyses whose line count differs
+	UnchangedFiles int
+	Files          []*FileDelta // Added, deleted and modified files, largest change first
+	Directories    []*GroupDelta
+	Languages      []*GroupDelta
+}
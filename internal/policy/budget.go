@@ -0,0 +1,192 @@
+package policy
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+// Budget rule IDs
+const (
+	RuleMaxAdditions    = "max-additions"
+	RuleMaxDeletions    = "max-deletions"
+	RuleMaxChangedFiles = "max-changed-files"
+	RuleMaxNetChange    = "max-net-change"
+	RuleMinTestRatio    = "min-test-ratio"
+)
+
+// Limits caps the size of a change. Nil fields are unlimited.
+type Limits struct {
+	MaxAdditions    *int `mapstructure:"max-additions"`
+	MaxDeletions    *int `mapstructure:"max-deletions"`
+	MaxChangedFiles *int `mapstructure:"max-changed-files"`
+	MaxNetChange    *int `mapstructure:"max-net-change"`
+	MinTestRatio    *int `mapstructure:"min-test-ratio"` // Test lines added per 100 production lines added
+}
+
+// PathBudget overrides the limits for files matching a glob such as "docs/**"
+type PathBudget struct {
+	Path   string `mapstructure:"path"`
+	Limits `mapstructure:",squash"`
+}
+
+// OtherFilesScope labels the violations of the other-files budget
+const OtherFilesScope = "other-files"
+
+// Budgets is the budgets section of .diffloc.yaml. The top-level limits apply
+// to the whole change. Each changed file also counts towards the first path
+// budget it matches, or towards OtherFiles if none does.
+type Budgets struct {
+	Limits     `mapstructure:",squash"`
+	OtherFiles Limits       `mapstructure:"other-files"`
+	Paths      []PathBudget `mapstructure:"paths"`
+}
+
+// IsZero reports whether no limit is configured
+func (b Budgets) IsZero() bool {
+	if !b.Limits.isZero() || !b.OtherFiles.isZero() {
+		return false
+	}
+	for _, p := range b.Paths {
+		if !p.Limits.isZero() {
+			return false
+		}
+	}
+	return true
+}
+
+func (l Limits) isZero() bool {
+	return l.MaxAdditions == nil && l.MaxDeletions == nil && l.MaxChangedFiles == nil && l.MaxNetChange == nil &&
+		l.MinTestRatio == nil
+}
+
+// compilePathGlob compiles a slash-separated glob; a trailing slash covers everything below the directory
+func compilePathGlob(pattern string) (glob.Glob, error) {
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+	return glob.Compile(pattern, '/')
+}
+
+type budgetTotals struct {
+	additions     int
+	deletions     int
+	changedFiles  int
+	testAdditions int
+}
+
+// CheckBudgets returns the budgets exceeded by the changed files
+func CheckBudgets(changed []*model.FileInfo, budgets Budgets) ([]*model.Violation, error) {
+	globs := make([]glob.Glob, len(budgets.Paths))
+	for i, p := range budgets.Paths {
+		g, err := compilePathGlob(p.Path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid budget path %q: %w", p.Path, err)
+		}
+		globs[i] = g
+	}
+
+	var whole, other budgetTotals
+	paths := make([]budgetTotals, len(budgets.Paths))
+	for _, file := range changed {
+		scope := &other
+		path := filepath.ToSlash(file.Path)
+		for i, g := range globs {
+			if g.Match(path) {
+				scope = &paths[i]
+				break
+			}
+		}
+
+		whole.add(file)
+		scope.add(file)
+	}
+
+	var violations []*model.Violation
+	violations = append(violations, checkLimits("", budgets.Limits, whole)...)
+	for i, p := range budgets.Paths {
+		violations = append(violations, checkLimits(p.Path, p.Limits, paths[i])...)
+	}
+	violations = append(violations, checkLimits(OtherFilesScope, budgets.OtherFiles, other)...)
+	return violations, nil
+}
+
+func (t *budgetTotals) add(file *model.FileInfo) {
+	t.additions += file.Additions
+	t.deletions += file.Deletions
+	t.changedFiles++
+	if file.IsTest {
+		t.testAdditions += file.Additions
+	}
+}
+
+// checkLimits compares the totals of one scope against its limits; an empty
+// scope is the whole change
+func checkLimits(scope string, limits Limits, t budgetTotals) []*model.Violation {
+	checks := []struct {
+		rule   string
+		limit  *int
+		actual int
+		one    string
+		many   string
+	}{
+		{RuleMaxAdditions, limits.MaxAdditions, t.additions, "line added", "lines added"},
+		{RuleMaxDeletions, limits.MaxDeletions, t.deletions, "line removed", "lines removed"},
+		{RuleMaxChangedFiles, limits.MaxChangedFiles, t.changedFiles, "file changed", "files changed"},
+		{RuleMaxNetChange, limits.MaxNetChange, t.additions - t.deletions, "net line changed", "net lines changed"},
+	}
+
+	prefix := ""
+	if scope != "" {
+		prefix = scope + ": "
+	}
+
+	var violations []*model.Violation
+	for _, c := range checks {
+		if c.limit == nil || c.actual <= *c.limit {
+			continue
+		}
+
+		what := c.many
+		if c.actual == 1 {
+			what = c.one
+		}
+		message := fmt.Sprintf("%s%d %s, budget is %d", prefix, c.actual, what, *c.limit)
+		violations = append(violations, &model.Violation{
+			Rule:    c.rule,
+			Scope:   scope,
+			Message: message,
+			Limit:   *c.limit,
+			Actual:  c.actual,
+		})
+	}
+
+	// The ratio only applies to changes that add production code
+	prodAdditions := t.additions - t.testAdditions
+	if limits.MinTestRatio != nil && prodAdditions > 0 {
+		ratio := t.testAdditions * 100 / prodAdditions
+		if ratio < *limits.MinTestRatio {
+			message := fmt.Sprintf("%s%s added for %s of production code (%d%%), minimum is %d%%",
+				prefix, countTestLines(t.testAdditions), countLines(prodAdditions), ratio, *limits.MinTestRatio)
+			violations = append(violations, &model.Violation{
+				Rule:    RuleMinTestRatio,
+				Scope:   scope,
+				Message: message,
+				Limit:   *limits.MinTestRatio,
+				Actual:  ratio,
+			})
+		}
+	}
+	return violations
+}
+
+// countTestLines formats a test line count with the right plural
+func countTestLines(n int) string {
+	if n == 1 {
+		return "1 test line"
+	}
+	return fmt.Sprintf("%d test lines", n)
+}
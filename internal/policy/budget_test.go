@@ -0,0 +1,155 @@
+package policy
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+func intp(n int) *int { return &n }
+
+// violationList formats violations as "rule scope path actual/limit" for comparison
+func violationList(violations []*model.Violation) []string {
+	var list []string
+	for _, v := range violations {
+		list = append(list, fmt.Sprintf("%s %q %q %d/%d", v.Rule, v.Scope, v.Path, v.Actual, v.Limit))
+	}
+	return list
+}
+
+func TestCheckBudgets(t *testing.T) {
+	changed := []*model.FileInfo{
+		{Path: "cmd/app/main.go", Additions: 30, Deletions: 10},
+		{Path: "cmd/app/main_test.go", Additions: 6, IsTest: true},
+		{Path: "docs/guide.md", Additions: 200, Deletions: 150},
+		{Path: "docs/api/index.md", Additions: 20},
+	}
+
+	tests := []struct {
+		name    string
+		budgets Budgets
+		want    []string
+	}{
+		{
+			name:    "within budget",
+			budgets: Budgets{Limits: Limits{MaxAdditions: intp(500), MaxChangedFiles: intp(4)}},
+		},
+		{
+			name: "whole change",
+			budgets: Budgets{Limits: Limits{
+				MaxAdditions: intp(100), MaxDeletions: intp(160), MaxChangedFiles: intp(3), MaxNetChange: intp(90),
+			}},
+			want: []string{
+				`max-additions "" "" 256/100`,
+				`max-changed-files "" "" 4/3`,
+				`max-net-change "" "" 96/90`,
+			},
+		},
+		{
+			name: "path scope",
+			budgets: Budgets{
+				Limits: Limits{MaxAdditions: intp(300)},
+				Paths:  []PathBudget{{Path: "docs/", Limits: Limits{MaxAdditions: intp(100)}}},
+			},
+			want: []string{`max-additions "docs/" "" 220/100`},
+		},
+		{
+			name: "path files count towards the whole change",
+			budgets: Budgets{
+				Limits: Limits{MaxAdditions: intp(40)},
+				Paths:  []PathBudget{{Path: "docs/", Limits: Limits{MaxAdditions: intp(1000)}}},
+			},
+			want: []string{`max-additions "" "" 256/40`},
+		},
+		{
+			name: "other files",
+			budgets: Budgets{
+				Paths:      []PathBudget{{Path: "docs/", Limits: Limits{MaxAdditions: intp(1000)}}},
+				OtherFiles: Limits{MaxAdditions: intp(30), MaxChangedFiles: intp(2)},
+			},
+			want: []string{`max-additions "other-files" "" 36/30`},
+		},
+		{
+			name: "first matching path wins",
+			budgets: Budgets{Paths: []PathBudget{
+				{Path: "docs/api/**", Limits: Limits{MaxChangedFiles: intp(0)}},
+				{Path: "docs/**", Limits: Limits{MaxChangedFiles: intp(1)}},
+			}},
+			want: []string{`max-changed-files "docs/api/**" "" 1/0`},
+		},
+		{
+			name: "path budgets check only their own limits",
+			budgets: Budgets{
+				Limits: Limits{MaxDeletions: intp(160)},
+				Paths:  []PathBudget{{Path: "docs/", Limits: Limits{MaxAdditions: intp(1000)}}},
+			},
+		},
+		{
+			name:    "test ratio",
+			budgets: Budgets{Paths: []PathBudget{{Path: "cmd/**", Limits: Limits{MinTestRatio: intp(25)}}}},
+			want:    []string{`min-test-ratio "cmd/**" "" 20/25`},
+		},
+		{
+			name:    "test ratio met",
+			budgets: Budgets{Paths: []PathBudget{{Path: "cmd/**", Limits: Limits{MinTestRatio: intp(20)}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		violations, err := CheckBudgets(changed, tt.budgets)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got := violationList(violations); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckBudgetsTestRatioNeedsProductionLines(t *testing.T) {
+	budgets := Budgets{Limits: Limits{MinTestRatio: intp(50)}}
+
+	for _, changed := range [][]*model.FileInfo{
+		nil,
+		{{Path: "calc_test.go", Additions: 12, IsTest: true}},
+		{{Path: "calc.go", Deletions: 40}},
+	} {
+		violations, err := CheckBudgets(changed, budgets)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(violations) > 0 {
+			t.Errorf("CheckBudgets(%d files) = %q, want no violations without production additions",
+				len(changed), violationList(violations))
+		}
+	}
+
+	violations, err := CheckBudgets([]*model.FileInfo{{Path: "calc.go", Additions: 10}}, budgets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := violationList(violations), []string{`min-test-ratio "" "" 0/50`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := violations[0].Message, "0 test lines added for 10 lines of production code (0%), minimum is 50%"; got != want {
+		t.Errorf("message = %q, want %q", got, want)
+	}
+}
+
+func TestCheckBudgetsInvalidPath(t *testing.T) {
+	if _, err := CheckBudgets(nil, Budgets{Paths: []PathBudget{{Path: "docs/[", Limits: Limits{MaxAdditions: intp(1)}}}}); err == nil {
+		t.Error("CheckBudgets with an invalid glob succeeded, want an error")
+	}
+}
+
+func TestBudgetsIsZero(t *testing.T) {
+	if !(Budgets{}).IsZero() {
+		t.Error("empty budgets are not zero")
+	}
+	if (Budgets{OtherFiles: Limits{MaxAdditions: intp(10)}}).IsZero() {
+		t.Error("budgets with an other-files limit are zero")
+	}
+}
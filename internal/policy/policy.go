@@ -0,0 +1,27 @@
+package policy
+
+// Severity levels of rules, as used by SARIF
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+)
+
+// Rule describes a budget or file rule for reports that list rule metadata
+type Rule struct {
+	ID          string
+	Name        string
+	Description string
+	Level       string
+}
+
+// Rules lists every rule diffloc can report, budgets first
+var Rules = []Rule{
+	{RuleMaxAdditions, "MaxAdditions", "The change adds more lines than the budget allows.", LevelError},
+	{RuleMaxDeletions, "MaxDeletions", "The change removes more lines than the budget allows.", LevelError},
+	{RuleMaxChangedFiles, "MaxChangedFiles", "The change touches more files than the budget allows.", LevelError},
+	{RuleMaxNetChange, "MaxNetChange", "Lines added minus lines removed exceed the budget.", LevelError},
+	{RuleMinTestRatio, "MinTestRatio", "Too few test lines were added for the production lines added.", LevelError},
+	{RuleMaxFileLines, "MaxFileLines", "The file is longer than the configured maximum.", LevelWarning},
+	{RuleMaxFileGrowth, "MaxFileGrowth", "The file grew by more than the configured percentage of its size at HEAD.", LevelWarning},
+	{RuleMaxNewFileLines, "MaxNewFileLines", "The new file is longer than the configured maximum for new files.", LevelWarning},
+}
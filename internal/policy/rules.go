@@ -0,0 +1,136 @@
+package policy
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/nodelike/diffloc/internal/analyzer"
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+// File rule IDs
+const (
+	RuleMaxFileLines    = "max-file-lines"
+	RuleMaxFileGrowth   = "max-file-growth"
+	RuleMaxNewFileLines = "max-new-file-lines"
+)
+
+// FileRule limits the size of the files matching Path and Lang; an empty
+// selector matches every file. Nil limits are not checked.
+type FileRule struct {
+	Path            string `mapstructure:"path"`
+	Lang            string `mapstructure:"lang"`
+	MaxLines        *int   `mapstructure:"max-lines"`
+	MaxGrowth       *int   `mapstructure:"max-growth"` // Percent of the HEAD size
+	MaxNewFileLines *int   `mapstructure:"max-new-file-lines"`
+}
+
+// compiledRule is a FileRule with its path glob compiled
+type compiledRule struct {
+	FileRule
+	glob glob.Glob
+}
+
+func (r compiledRule) matches(file *model.FileInfo) bool {
+	if r.glob != nil && !r.glob.Match(filepath.ToSlash(file.Path)) {
+		return false
+	}
+	return r.Lang == "" || strings.EqualFold(r.Lang, analyzer.Language(file.Path))
+}
+
+// RuleSet is a list of file rules with their path globs compiled, for
+// checking files one at a time
+type RuleSet struct {
+	rules []compiledRule
+}
+
+// CompileRules compiles the path globs of rules
+func CompileRules(rules []FileRule) (*RuleSet, error) {
+	compiled := make([]compiledRule, len(rules))
+	for i, rule := range rules {
+		compiled[i].FileRule = rule
+		if rule.Path != "" {
+			g, err := compilePathGlob(rule.Path)
+			if err != nil {
+				return nil, fmt.Errorf("invalid rule path %q: %w", rule.Path, err)
+			}
+			compiled[i].glob = g
+		}
+	}
+	return &RuleSet{rules: compiled}, nil
+}
+
+// Check evaluates the rules against a file. For each limit the last matching
+// rule that sets it wins, so specific rules should follow general ones.
+// Deleted files are skipped.
+func (s *RuleSet) Check(file *model.FileInfo) []*model.Violation {
+	if file.Status == model.StatusDeleted {
+		return nil
+	}
+
+	var limits FileRule
+	for _, rule := range s.rules {
+		if !rule.matches(file) {
+			continue
+		}
+		if rule.MaxLines != nil {
+			limits.MaxLines = rule.MaxLines
+		}
+		if rule.MaxGrowth != nil {
+			limits.MaxGrowth = rule.MaxGrowth
+		}
+		if rule.MaxNewFileLines != nil {
+			limits.MaxNewFileLines = rule.MaxNewFileLines
+		}
+	}
+
+	return checkFile(file, limits)
+}
+
+// checkFile compares a file against the limits that apply to it
+func checkFile(file *model.FileInfo, limits FileRule) []*model.Violation {
+	var violations []*model.Violation
+	add := func(rule string, limit, actual int, message string) {
+		violations = append(violations, &model.Violation{
+			Rule:    rule,
+			Path:    file.Path,
+			Message: message,
+			Limit:   limit,
+			Actual:  actual,
+		})
+	}
+
+	if limits.MaxLines != nil && file.Lines > *limits.MaxLines {
+		add(RuleMaxFileLines, *limits.MaxLines, file.Lines,
+			fmt.Sprintf("%s has %s, limit is %d", file.Path, countLines(file.Lines), *limits.MaxLines))
+	}
+
+	if file.Status == model.StatusAdded {
+		if limits.MaxNewFileLines != nil && file.Lines > *limits.MaxNewFileLines {
+			add(RuleMaxNewFileLines, *limits.MaxNewFileLines, file.Lines,
+				fmt.Sprintf("new file %s has %s, limit is %d", file.Path, countLines(file.Lines), *limits.MaxNewFileLines))
+		}
+		return violations
+	}
+
+	headLines := file.HeadLines
+	if limits.MaxGrowth != nil && file.IsChanged && headLines > 0 {
+		growth := (file.Lines - headLines) * 100 / headLines
+		if growth > *limits.MaxGrowth {
+			add(RuleMaxFileGrowth, *limits.MaxGrowth, growth,
+				fmt.Sprintf("%s grew by %d%% (%d to %d lines), limit is %d%%", file.Path, growth, headLines, file.Lines, *limits.MaxGrowth))
+		}
+	}
+
+	return violations
+}
+
+// countLines formats a line count with the right plural
+func countLines(n int) string {
+	if n == 1 {
+		return "1 line"
+	}
+	return fmt.Sprintf("%d lines", n)
+}
@@ -0,0 +1,76 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+func TestRuleSetCheck(t *testing.T) {
+	rules, err := CompileRules([]FileRule{
+		{MaxLines: intp(500), MaxGrowth: intp(50)},
+		{Lang: "go", MaxNewFileLines: intp(100)},
+		{Path: "internal/gen/", MaxLines: intp(5000), MaxGrowth: intp(1000)},
+	})
+	if err != nil {
+		t.Fatalf("CompileRules: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		file *model.FileInfo
+		want []string
+	}{
+		{
+			name: "unchanged file over the limit",
+			file: &model.FileInfo{Path: "big.py", Lines: 600, Status: model.StatusUnchanged},
+			want: []string{`max-file-lines "" "big.py" 600/500`},
+		},
+		{
+			name: "new Go file",
+			file: &model.FileInfo{Path: "cmd/new.go", Lines: 150, Additions: 150, IsChanged: true, Status: model.StatusAdded},
+			want: []string{`max-new-file-lines "" "cmd/new.go" 150/100`},
+		},
+		{
+			name: "new file of another language",
+			file: &model.FileInfo{Path: "web/new.js", Lines: 150, Additions: 150, IsChanged: true, Status: model.StatusAdded},
+		},
+		{
+			name: "growth",
+			file: &model.FileInfo{Path: "calc.go", Lines: 160, HeadLines: 100, Additions: 70, Deletions: 10, IsChanged: true, Status: model.StatusModified},
+			want: []string{`max-file-growth "" "calc.go" 60/50`},
+		},
+		{
+			name: "growth within limit",
+			file: &model.FileInfo{Path: "calc.go", Lines: 150, HeadLines: 100, Additions: 60, Deletions: 10, IsChanged: true, Status: model.StatusModified},
+		},
+		{
+			// With --ignore-gofmt the counts leave out reformatted lines, so
+			// only the HEAD line count tells how much the file grew
+			name: "growth ignoring gofmt-only changes",
+			file: &model.FileInfo{Path: "calc.go", Lines: 160, HeadLines: 100, Additions: 5, IsChanged: true, Status: model.StatusModified},
+			want: []string{`max-file-growth "" "calc.go" 60/50`},
+		},
+		{
+			name: "later rule overrides",
+			file: &model.FileInfo{Path: "internal/gen/tables.go", Lines: 4000, HeadLines: 1000, Additions: 3000, IsChanged: true, Status: model.StatusModified},
+		},
+		{
+			name: "deleted file",
+			file: &model.FileInfo{Path: "old.go", HeadLines: 900, Deletions: 900, IsChanged: true, Status: model.StatusDeleted},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := violationList(rules.Check(tt.file)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCompileRulesInvalidPath(t *testing.T) {
+	if _, err := CompileRules([]FileRule{{Path: "src/[", MaxLines: intp(1)}}); err == nil {
+		t.Error("CompileRules with an invalid glob succeeded, want an error")
+	}
+}
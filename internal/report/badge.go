@@ -0,0 +1,233 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Badge metrics accepted by WriteBadge
+const (
+	BadgeLines    = "lines"
+	BadgeLanguage = "lang"
+	BadgeDiff     = "diff"
+)
+
+// badgeColors are the shields.io named colors
+var badgeColors = map[string]string{
+	"brightgreen": "#4c1",
+	"green":       "#97ca00",
+	"yellowgreen": "#a4a61d",
+	"yellow":      "#dfb317",
+	"orange":      "#fe7d37",
+	"red":         "#e05d44",
+	"blue":        "#007ec6",
+	"lightgrey":   "#9f9f9f",
+	"lightgray":   "#9f9f9f",
+	"grey":        "#555",
+	"gray":        "#555",
+}
+
+var hexColorRe = regexp.MustCompile(`^#?([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// Threshold selects a badge color for values up to Max
+type Threshold struct {
+	Max   int
+	Color string
+}
+
+// BadgeOptions controls the badge written by WriteBadge
+type BadgeOptions struct {
+	Metric     string // BadgeLines, BadgeLanguage or BadgeDiff
+	Language   string // Language counted by BadgeLanguage, e.g. "Go"
+	Label      string // Left-hand text; defaults depend on the metric
+	Color      string // Fixed message color; overrides Thresholds
+	LabelColor string
+	Thresholds []Threshold // Ordered by Max; the last entry may use Max < 0 as the fallback, otherwise unmatched values are lightgrey
+}
+
+// unmatchedColor is used when the value exceeds every threshold and there is no fallback entry
+const unmatchedColor = "lightgrey"
+
+// DefaultDiffThresholds color diff badges by total changed lines
+var DefaultDiffThresholds = []Threshold{
+	{Max: 200, Color: "brightgreen"},
+	{Max: 500, Color: "yellow"},
+	{Max: 1000, Color: "orange"},
+	{Max: -1, Color: "red"},
+}
+
+// ParseThresholds parses a list such as "200:brightgreen,500:yellow,red". Each
+// entry applies to values up to its limit; a final entry without a limit
+// applies to everything above; without one, larger values are lightgrey.
+func ParseThresholds(spec string) ([]Threshold, error) {
+	var thresholds []Threshold
+	entries := strings.Split(spec, ",")
+	for i, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		limit, color, hasLimit := strings.Cut(entry, ":")
+		if !hasLimit {
+			if i != len(entries)-1 {
+				return nil, fmt.Errorf("invalid threshold %q: only the last entry may omit the limit", entry)
+			}
+			thresholds = append(thresholds, Threshold{Max: -1, Color: entry})
+			continue
+		}
+
+		upper, err := strconv.Atoi(strings.TrimSpace(limit))
+		if err != nil || upper < 0 {
+			return nil, fmt.Errorf("invalid threshold limit %q (expected a non-negative integer)", limit)
+		}
+		if n := len(thresholds); n > 0 && upper <= thresholds[n-1].Max {
+			return nil, fmt.Errorf("threshold limits must be increasing: %d after %d", upper, thresholds[n-1].Max)
+		}
+		thresholds = append(thresholds, Threshold{Max: upper, Color: strings.TrimSpace(color)})
+	}
+
+	for _, t := range thresholds {
+		if _, err := badgeColor(t.Color); err != nil {
+			return nil, err
+		}
+	}
+	return thresholds, nil
+}
+
+// WriteBadge writes a shields.io-style flat SVG badge for one metric of the report
+func WriteBadge(w io.Writer, r *Report, opts BadgeOptions) error {
+	var label, message string
+	var value int
+	color := "blue"
+
+	switch opts.Metric {
+	case BadgeLines, "":
+		value = r.Summary.TotalLines
+		label, message = "lines of code", humanize(value)
+	case BadgeLanguage:
+		if opts.Language == "" {
+			return fmt.Errorf("the lang badge requires a language")
+		}
+		language := ""
+		for _, file := range r.Files {
+			if strings.EqualFold(file.Language, opts.Language) {
+				value += file.Lines
+				language = file.Language
+			}
+		}
+		if language == "" {
+			return fmt.Errorf("no %s files found", opts.Language)
+		}
+		label, message = language+" lines", humanize(value)
+	case BadgeDiff:
+		if !r.Metadata.Git {
+			return fmt.Errorf("the diff badge requires a Git repository")
+		}
+		value = r.Summary.Additions + r.Summary.Deletions
+		label = "diff"
+		message = "+" + humanize(r.Summary.Additions) + " -" + humanize(r.Summary.Deletions)
+		if opts.Thresholds == nil {
+			opts.Thresholds = DefaultDiffThresholds
+		}
+	default:
+		return fmt.Errorf("unknown badge metric %q (expected lines, lang or diff)", opts.Metric)
+	}
+
+	if opts.Label != "" {
+		label = opts.Label
+	}
+	if len(opts.Thresholds) > 0 {
+		color = unmatchedColor
+		for _, t := range opts.Thresholds {
+			if t.Max < 0 || value <= t.Max {
+				color = t.Color
+				break
+			}
+		}
+	}
+	if opts.Color != "" {
+		color = opts.Color
+	}
+	labelColor := opts.LabelColor
+	if labelColor == "" {
+		labelColor = "grey"
+	}
+
+	messageFill, err := badgeColor(color)
+	if err != nil {
+		return err
+	}
+	labelFill, err := badgeColor(labelColor)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, renderBadge(label, message, labelFill, messageFill))
+	return err
+}
+
+// badgeColor resolves a shields.io color name or hex value to an SVG fill
+func badgeColor(name string) (string, error) {
+	if fill, ok := badgeColors[strings.ToLower(name)]; ok {
+		return fill, nil
+	}
+	if hexColorRe.MatchString(name) {
+		return "#" + strings.TrimPrefix(name, "#"), nil
+	}
+	return "", fmt.Errorf("unknown badge color %q (expected a shields.io color name or hex value)", name)
+}
+
+// renderBadge lays out a two-part flat badge with 5px padding around each text
+func renderBadge(label, message, labelFill, messageFill string) string {
+	labelWidth := textWidth(label) + 10
+	messageWidth := textWidth(message) + 10
+	width := labelWidth + messageWidth
+
+	title := html.EscapeString(label + ": " + message)
+	label = html.EscapeString(label)
+	message = html.EscapeString(message)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s">`, width, title)
+	fmt.Fprintf(&b, `<title>%s</title>`, title)
+	b.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+	fmt.Fprintf(&b, `<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`, width)
+	fmt.Fprintf(&b, `<g clip-path="url(#r)"><rect width="%d" height="20" fill="%s"/><rect x="%d" width="%d" height="20" fill="%s"/><rect width="%d" height="20" fill="url(#s)"/></g>`,
+		labelWidth, labelFill, labelWidth, messageWidth, messageFill, width)
+	b.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" text-rendering="geometricPrecision" font-size="11">`)
+	for _, part := range []struct {
+		x    float64
+		text string
+	}{
+		{float64(labelWidth) / 2, label},
+		{float64(labelWidth) + float64(messageWidth)/2, message},
+	} {
+		fmt.Fprintf(&b, `<text x="%.1f" y="15" fill="#010101" fill-opacity=".3">%s</text>`, part.x, part.text)
+		fmt.Fprintf(&b, `<text x="%.1f" y="14">%s</text>`, part.x, part.text)
+	}
+	b.WriteString("</g></svg>\n")
+	return b.String()
+}
+
+// textWidth approximates the rendered width in pixels of s in 11px Verdana
+func textWidth(s string) int {
+	width := 0.0
+	for _, r := range s {
+		switch {
+		case strings.ContainsRune("iIl.,:;|!'", r):
+			width += 3.5
+		case strings.ContainsRune(" fjtr()[]-/", r):
+			width += 4.5
+		case strings.ContainsRune("mwMW", r):
+			width += 10.5
+		case r >= 'A' && r <= 'Z':
+			width += 7.5
+		case r == '+' || r == '#' || r == '%':
+			width += 9
+		default:
+			width += 7
+		}
+	}
+	return int(width + 0.5)
+}
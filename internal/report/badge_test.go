@@ -0,0 +1,102 @@
+package report
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseThresholds(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []Threshold
+	}{
+		{"red", []Threshold{{-1, "red"}}},
+		{"100:green", []Threshold{{100, "green"}}},
+		{"200:brightgreen,500:yellow,red", []Threshold{{200, "brightgreen"}, {500, "yellow"}, {-1, "red"}}},
+		{" 0:green , 10 : orange , #ff0000 ", []Threshold{{0, "green"}, {10, "orange"}, {-1, "#ff0000"}}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseThresholds(tt.spec)
+		if err != nil {
+			t.Errorf("ParseThresholds(%q): %v", tt.spec, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseThresholds(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+
+	for _, spec := range []string{
+		"",
+		"red,200:green",
+		"x:green",
+		"-5:green",
+		"500:green,200:red",
+		"200:green,200:red",
+		"200:purplish",
+	} {
+		if _, err := ParseThresholds(spec); err == nil {
+			t.Errorf("ParseThresholds(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestWriteBadgeDiffColor(t *testing.T) {
+	tests := []struct {
+		additions, deletions int
+		fill                 string
+	}{
+		{0, 0, "#4c1"},
+		{150, 50, "#4c1"},
+		{150, 51, "#dfb317"},
+		{900, 100, "#fe7d37"},
+		{1000, 1, "#e05d44"},
+	}
+
+	for _, tt := range tests {
+		r := &Report{}
+		r.Metadata.Git = true
+		r.Summary.Additions = tt.additions
+		r.Summary.Deletions = tt.deletions
+
+		var buf bytes.Buffer
+		if err := WriteBadge(&buf, r, BadgeOptions{Metric: BadgeDiff}); err != nil {
+			t.Fatalf("WriteBadge: %v", err)
+		}
+		if !strings.Contains(buf.String(), `fill="`+tt.fill+`"`) {
+			t.Errorf("diff badge for +%d -%d is not filled with %s:\n%s", tt.additions, tt.deletions, tt.fill, buf.String())
+		}
+	}
+}
+
+func TestWriteBadgeWithoutFallback(t *testing.T) {
+	thresholds, err := ParseThresholds("100:brightgreen,400:yellow")
+	if err != nil {
+		t.Fatalf("ParseThresholds: %v", err)
+	}
+
+	tests := []struct {
+		lines int
+		fill  string
+	}{
+		{100, "#4c1"},
+		{400, "#dfb317"},
+		{401, "#9f9f9f"},
+	}
+
+	for _, tt := range tests {
+		r := &Report{}
+		r.Summary.TotalLines = tt.lines
+
+		var buf bytes.Buffer
+		if err := WriteBadge(&buf, r, BadgeOptions{Thresholds: thresholds}); err != nil {
+			t.Fatalf("WriteBadge: %v", err)
+		}
+		if !strings.Contains(buf.String(), `fill="`+tt.fill+`"`) {
+			t.Errorf("badge for %d lines is not filled with %s:\n%s", tt.lines, tt.fill, buf.String())
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+// CompareSchema identifies diffloc JSON comparisons
+const CompareSchema = "diffloc/compare"
+
+// CompareSchemaVersion is the major version of the comparison format,
+// versioned like SchemaVersion
+const CompareSchemaVersion = 1
+
+// Comparison is the JSON representation of the changes between two reports
+type Comparison struct {
+	Schema        string         `json:"schema"`
+	SchemaVersion int            `json:"schema_version"`
+	Old           CompareSide    `json:"old"`
+	New           CompareSide    `json:"new"`
+	Summary       CompareSummary `json:"summary"`
+	Files         []FileDelta    `json:"files"`
+	Directories   []GroupDelta   `json:"directories"`
+	Languages     []GroupDelta   `json:"languages"`
+}
+
+// CompareSide identifies one of the compared reports
+type CompareSide struct {
+	Report      string    `json:"report"` // File name the report was read from
+	GeneratedAt time.Time `json:"generated_at"`
+	RepoRoot    string    `json:"repo_root"`
+	HeadSHA     string    `json:"head_sha,omitempty"`
+	Branch      string    `json:"branch,omitempty"`
+	ToolVersion string    `json:"tool_version"`
+}
+
+// Delta is a count in the old and the new report
+type Delta struct {
+	Old    int `json:"old"`
+	New    int `json:"new"`
+	Change int `json:"change"`
+}
+
+// CompareSummary holds the totals of a comparison
+type CompareSummary struct {
+	Files          Delta `json:"files"`
+	Lines          Delta `json:"lines"`
+	AddedFiles     int   `json:"added_files"`
+	DeletedFiles   int   `json:"deleted_files"`
+	ModifiedFiles  int   `json:"modified_files"`
+	UnchangedFiles int   `json:"unchanged_files"`
+}
+
+// FileDelta is a file added, deleted or resized between the reports
+type FileDelta struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+	Lines  Delta  `json:"lines"`
+}
+
+// GroupDelta compares a directory or language between the reports
+type GroupDelta struct {
+	Name  string `json:"name"`
+	Files Delta  `json:"files"`
+	Lines Delta  `json:"lines"`
+}
+
+// NewComparison builds the JSON representation of a comparison between the
+// reports read from oldName and newName
+func NewComparison(c *model.Comparison, oldName string, oldReport *Report, newName string, newReport *Report) *Comparison {
+	out := &Comparison{
+		Schema:        CompareSchema,
+		SchemaVersion: CompareSchemaVersion,
+		Old:           newCompareSide(oldName, oldReport),
+		New:           newCompareSide(newName, newReport),
+		Summary: CompareSummary{
+			Files:          newDelta(c.Total.Files),
+			Lines:          newDelta(c.Total.Lines),
+			AddedFiles:     c.AddedFiles,
+			DeletedFiles:   c.DeletedFiles,
+			ModifiedFiles:  c.ModifiedFiles,
+			UnchangedFiles: c.UnchangedFiles,
+		},
+		Files:       make([]FileDelta, 0, len(c.Files)),
+		Directories: newGroupDeltas(c.Directories),
+		Languages:   newGroupDeltas(c.Languages),
+	}
+
+	for _, file := range c.Files {
+		out.Files = append(out.Files, FileDelta{Path: file.Path, Status: file.Status, Lines: newDelta(file.Lines)})
+	}
+
+	return out
+}
+
+func newCompareSide(name string, r *Report) CompareSide {
+	return CompareSide{
+		Report:      name,
+		GeneratedAt: r.Metadata.GeneratedAt,
+		RepoRoot:    r.Metadata.RepoRoot,
+		HeadSHA:     r.Metadata.HeadSHA,
+		Branch:      r.Metadata.Branch,
+		ToolVersion: r.Metadata.ToolVersion,
+	}
+}
+
+func newDelta(d model.Delta) Delta {
+	return Delta{Old: d.Old, New: d.New, Change: d.Change()}
+}
+
+func newGroupDeltas(groups []*model.GroupDelta) []GroupDelta {
+	out := make([]GroupDelta, 0, len(groups))
+	for _, g := range groups {
+		out = append(out, GroupDelta{Name: g.Name, Files: newDelta(g.Files), Lines: newDelta(g.Lines)})
+	}
+	return out
+}
+
+// WriteComparisonJSON writes a comparison as indented JSON
+func WriteComparisonJSON(w io.Writer, c *Comparison) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(c)
+}
+
+// WriteComparisonMarkdown writes a growth report suited to release notes: the
+// summary table, line changes per language and directory, the largest file
+// changes, and every added, deleted and resized file in a collapsible section.
+func WriteComparisonMarkdown(w io.Writer, c *Comparison, opts MarkdownOptions) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "### 📈 diffloc comparison: %s → %s\n\n", compareLabel(c.Old), compareLabel(c.New))
+
+	s := c.Summary
+	b.WriteString("| | Old | New | Change |\n")
+	b.WriteString("|---|---:|---:|---:|\n")
+	fmt.Fprintf(&b, "| Files | %s | %s | %s |\n", formatInt(s.Files.Old), formatInt(s.Files.New), formatSigned(s.Files.Change))
+	fmt.Fprintf(&b, "| Lines | %s | %s | %s |\n", formatInt(s.Lines.Old), formatInt(s.Lines.New), formatSigned(s.Lines.Change))
+	fmt.Fprintf(&b, "\n%s added, %s deleted, %s resized, %s unchanged.\n",
+		fileCount(s.AddedFiles), fileCount(s.DeletedFiles), fileCount(s.ModifiedFiles), fileCount(s.UnchangedFiles))
+
+	writeGroupDeltas(&b, "By language", c.Languages, markdownCell)
+	writeGroupDeltas(&b, "By directory", c.Directories, markdownPath)
+
+	if len(c.Files) > 0 && opts.Top > 0 {
+		top := c.Files
+		if len(top) > opts.Top {
+			top = top[:opts.Top]
+		}
+
+		fmt.Fprintf(&b, "\n#### Top %d file changes\n\n", len(top))
+		writeFileDeltas(&b, top)
+	}
+
+	if len(c.Files) > 0 {
+		fmt.Fprintf(&b, "\n<details>\n<summary>All %s added, deleted and resized files</summary>\n\n", formatInt(len(c.Files)))
+		writeFileDeltas(&b, c.Files)
+		b.WriteString("\n</details>\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeGroupDeltas(b *strings.Builder, title string, groups []GroupDelta, name func(string) string) {
+	if len(groups) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "\n#### %s\n\n", title)
+	b.WriteString("| Name | Files | Lines | Change |\n")
+	b.WriteString("|---|---:|---:|---:|\n")
+	for _, g := range groups {
+		fmt.Fprintf(b, "| %s | %s → %s | %s → %s | %s |\n", name(g.Name),
+			formatInt(g.Files.Old), formatInt(g.Files.New), formatInt(g.Lines.Old), formatInt(g.Lines.New), formatSigned(g.Lines.Change))
+	}
+}
+
+func writeFileDeltas(b *strings.Builder, files []FileDelta) {
+	b.WriteString("| File | Status | Old | New | Change |\n")
+	b.WriteString("|---|---|---:|---:|---:|\n")
+	for _, file := range files {
+		fmt.Fprintf(b, "| %s | %s | %s | %s | %s |\n", markdownPath(file.Path), file.Status,
+			formatInt(file.Lines.Old), formatInt(file.Lines.New), formatSigned(file.Lines.Change))
+	}
+}
+
+// compareLabel names a compared report by its branch and commit, falling back
+// to the file it was read from
+func compareLabel(side CompareSide) string {
+	switch {
+	case side.Branch != "" && side.HeadSHA != "":
+		return fmt.Sprintf("`%s@%s`", side.Branch, shortSHA(side.HeadSHA))
+	case side.HeadSHA != "":
+		return "`" + shortSHA(side.HeadSHA) + "`"
+	default:
+		return markdownPath(side.Report)
+	}
+}
+
+// markdownCell escapes text for use in a table cell
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+func fileCount(n int) string {
+	if n == 1 {
+		return "1 file"
+	}
+	return formatInt(n) + " files"
+}
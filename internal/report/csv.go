@@ -0,0 +1,113 @@
+package report
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// delimitedHeader lists the columns of CSV and TSV output
+var delimitedHeader = []string{"path", "status", "language", "lines", "additions", "deletions", "changed"}
+
+// delimitedGroupHeader lists the columns of CSV and TSV output for reports with groups
+var delimitedGroupHeader = []string{"group", "files", "changed_files", "lines", "additions", "deletions", "line_share", "change_share"}
+
+// WriteCSV writes one comma-separated row per file, or per group when the
+// report has groups, optionally followed by a totals row
+func WriteCSV(w io.Writer, r *Report, summaryRow bool) error {
+	return writeDelimited(w, r, ',', summaryRow)
+}
+
+// WriteTSV writes one tab-separated row per file, or per group when the
+// report has groups, optionally followed by a totals row
+func WriteTSV(w io.Writer, r *Report, summaryRow bool) error {
+	return writeDelimited(w, r, '\t', summaryRow)
+}
+
+func writeDelimited(w io.Writer, r *Report, comma rune, summaryRow bool) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+
+	if r.Groups != nil {
+		return writeDelimitedGroups(writer, r, summaryRow)
+	}
+
+	if err := writer.Write(delimitedHeader); err != nil {
+		return err
+	}
+
+	for _, file := range r.Files {
+		row := []string{
+			file.Path,
+			file.Status,
+			file.Language,
+			strconv.Itoa(file.Lines),
+			strconv.Itoa(file.Additions),
+			strconv.Itoa(file.Deletions),
+			strconv.FormatBool(file.Changed),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	if summaryRow {
+		row := []string{
+			"(total)",
+			"",
+			"",
+			strconv.Itoa(r.Summary.TotalLines),
+			strconv.Itoa(r.Summary.Additions),
+			strconv.Itoa(r.Summary.Deletions),
+			"",
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeDelimitedGroups writes one row per group of the report
+func writeDelimitedGroups(writer *csv.Writer, r *Report, summaryRow bool) error {
+	if err := writer.Write(delimitedGroupHeader); err != nil {
+		return err
+	}
+
+	for _, g := range r.Groups.Groups {
+		row := []string{
+			g.Name,
+			strconv.Itoa(g.Files),
+			strconv.Itoa(g.ChangedFiles),
+			strconv.Itoa(g.Lines),
+			strconv.Itoa(g.Additions),
+			strconv.Itoa(g.Deletions),
+			strconv.FormatFloat(g.LineShare, 'f', 1, 64),
+			strconv.FormatFloat(g.ChangeShare, 'f', 1, 64),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	if summaryRow {
+		row := []string{
+			"(total)",
+			strconv.Itoa(r.Summary.TotalFiles),
+			strconv.Itoa(r.Summary.ChangedFiles),
+			strconv.Itoa(r.Summary.TotalLines),
+			strconv.Itoa(r.Summary.Additions),
+			strconv.Itoa(r.Summary.Deletions),
+			"",
+			"",
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,46 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, testReport(), true); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	assertGolden(t, "report.csv", buf.Bytes())
+}
+
+func TestWriteTSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTSV(&buf, testReport(), false); err != nil {
+		t.Fatalf("WriteTSV: %v", err)
+	}
+	assertGolden(t, "report.tsv", buf.Bytes())
+}
+
+func TestWriteCSVQuotesFields(t *testing.T) {
+	r := testReport()
+	r.Files = []File{{Path: `docs/a, "b".md`, Status: "added", Language: "Markdown", Lines: 1, Additions: 1, Changed: true}}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, r, false); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+
+	want := "path,status,language,lines,additions,deletions,changed\n" +
+		`"docs/a, ""b"".md",added,Markdown,1,1,0,true` + "\n"
+	if buf.String() != want {
+		t.Errorf("WriteCSV =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteCSVGroups(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, testGroupedReport(), true); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	assertGolden(t, "report-groups.csv", buf.Bytes())
+}
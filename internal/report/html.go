@@ -0,0 +1,55 @@
+package report
+
+import (
+	_ "embed"
+	"encoding/json"
+	"html/template"
+	"io"
+	"path/filepath"
+)
+
+//go:embed templates/report.html.tmpl
+var htmlTemplate string
+
+// htmlData is the data passed to the HTML report template
+type htmlData struct {
+	Report *Report
+	Title  string
+	Data   template.JS
+}
+
+var htmlFuncs = template.FuncMap{
+	"num":      formatInt,
+	"signed":   formatSigned,
+	"shortSHA": shortSHA,
+}
+
+// shortSHA abbreviates a commit hash to 12 characters
+func shortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
+
+// WriteHTML writes a single-file HTML report with embedded CSS and JavaScript:
+// the summary, a sortable and filterable file table, a directory treemap and a
+// language pie chart. It needs no network access to render.
+func WriteHTML(w io.Writer, r *Report) error {
+	tmpl, err := template.New("report").Funcs(htmlFuncs).Parse(htmlTemplate)
+	if err != nil {
+		return err
+	}
+
+	// json.Marshal escapes <, > and &, so the payload cannot close the script element
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(w, htmlData{
+		Report: r,
+		Title:  filepath.Base(r.Metadata.RepoRoot),
+		Data:   template.JS(data),
+	})
+}
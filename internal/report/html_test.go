@@ -0,0 +1,76 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var reportDataRe = regexp.MustCompile(`(?s)<script type="application/json" id="report-data">(.*?)</script>`)
+
+func TestWriteHTML(t *testing.T) {
+	r := testReport()
+
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, r); err != nil {
+		t.Fatalf("WriteHTML: %v", err)
+	}
+	html := buf.String()
+
+	for _, want := range []string{
+		"<title>diffloc — app</title>",
+		"<code>/work/app</code>",
+		"branch <code>main</code>",
+		"HEAD <code>0123456789ab</code>",
+		"generated 2026-01-02 03:04 UTC by diffloc 1.2.3",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML report lacks %q", want)
+		}
+	}
+
+	// Self-contained: nothing is loaded from the network
+	if loc := regexp.MustCompile(`(src|href)="https?:`).FindStringIndex(html); loc != nil {
+		t.Errorf("HTML report loads a remote resource: %s", html[loc[0]:loc[1]+40])
+	}
+
+	match := reportDataRe.FindStringSubmatch(html)
+	if match == nil {
+		t.Fatal("HTML report has no report-data payload")
+	}
+	var payload Report
+	if err := json.Unmarshal([]byte(match[1]), &payload); err != nil {
+		t.Fatalf("report-data payload is not valid JSON: %v", err)
+	}
+	if !reflect.DeepEqual(&payload, r) {
+		t.Errorf("report-data payload differs from the report")
+	}
+}
+
+func TestWriteHTMLEscapesPayload(t *testing.T) {
+	r := testReport()
+	r.Files[0].Path = `</script><script>alert(1)</script>.go`
+
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, r); err != nil {
+		t.Fatalf("WriteHTML: %v", err)
+	}
+
+	if strings.Contains(buf.String(), "<script>alert(1)") {
+		t.Error("a file path closed the report-data script element")
+	}
+	match := reportDataRe.FindStringSubmatch(buf.String())
+	if match == nil {
+		t.Fatal("HTML report has no report-data payload")
+	}
+	var payload Report
+	if err := json.Unmarshal([]byte(match[1]), &payload); err != nil {
+		t.Fatalf("report-data payload is not valid JSON: %v", err)
+	}
+	if payload.Files[0].Path != r.Files[0].Path {
+		t.Errorf("payload path = %q, want %q", payload.Files[0].Path, r.Files[0].Path)
+	}
+}
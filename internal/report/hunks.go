@@ -0,0 +1,20 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// WriteHunks writes one line per changed hunk, with the file path followed by
+// a unified diff range header, e.g. "main.go @@ -10,2 +10,5 @@". Files are
+// ordered by path and hunks by position.
+func WriteHunks(w io.Writer, r *Report) error {
+	bw := bufio.NewWriter(w)
+	for _, file := range r.Files {
+		for _, hunk := range file.Hunks {
+			fmt.Fprintf(bw, "%s @@ -%d,%d +%d,%d @@\n", file.Path, hunk.OldStart, hunk.OldLines, hunk.NewStart, hunk.NewLines)
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,21 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteHunks(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteHunks(&buf, testReport()); err != nil {
+		t.Fatalf("WriteHunks: %v", err)
+	}
+
+	want := "cmd/app/main.go @@ -10,2 +10,8 @@\n" +
+		"cmd/app/main.go @@ -40,2 +46,4 @@\n" +
+		"internal/calc/calc_test.go @@ -0,0 +1,30 @@\n" +
+		"scripts/old.py @@ -1,3 +0,0 @@\n"
+	if buf.String() != want {
+		t.Errorf("WriteHunks =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
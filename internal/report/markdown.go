@@ -0,0 +1,193 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MarkdownOptions controls the Markdown report
+type MarkdownOptions struct {
+	Top int // Number of changed files listed in the top table
+}
+
+// barWidth is the number of blocks in a +/- bar
+const barWidth = 5
+
+// WriteMarkdown writes a compact report suited to pull request comments: a
+// summary table, the largest changes with +/- bars, the rollup table when the
+// report has groups, and the full list of changed files in a collapsible section.
+func WriteMarkdown(w io.Writer, r *Report, opts MarkdownOptions) error {
+	var b strings.Builder
+
+	b.WriteString("### 📊 diffloc report\n\n")
+	writeMarkdownSummary(&b, r)
+
+	changed := changedFiles(r)
+
+	if len(changed) > 0 && opts.Top > 0 {
+		top := changed
+		if len(top) > opts.Top {
+			top = top[:opts.Top]
+		}
+
+		fmt.Fprintf(&b, "\n#### Top %d changed files\n\n", len(top))
+		b.WriteString("| File | Added | Removed | |\n")
+		b.WriteString("|---|---:|---:|---|\n")
+		for _, file := range top {
+			fmt.Fprintf(&b, "| %s | +%s | -%s | %s |\n",
+				markdownPath(file.Path), formatInt(file.Additions), formatInt(file.Deletions), diffBar(file.Additions, file.Deletions))
+		}
+	}
+
+	if r.Groups != nil && len(r.Groups.Groups) > 0 {
+		groups := make([]Group, len(r.Groups.Groups))
+		copy(groups, r.Groups.Groups)
+		sort.SliceStable(groups, func(i, j int) bool {
+			ci := groups[i].Additions + groups[i].Deletions
+			cj := groups[j].Additions + groups[j].Deletions
+			if ci != cj {
+				return ci > cj
+			}
+			return groups[i].Lines > groups[j].Lines
+		})
+
+		fmt.Fprintf(&b, "\n#### By %s\n\n", groupingTitle(r.Groups.By))
+		b.WriteString("| Group | Files | Lines | Added | Removed | Share of change |\n")
+		b.WriteString("|---|---:|---:|---:|---:|---:|\n")
+		for _, g := range groups {
+			fmt.Fprintf(&b, "| %s | %s | %s | +%s | -%s | %.1f%% |\n",
+				markdownPath(g.Name), formatInt(g.Files), formatInt(g.Lines), formatInt(g.Additions), formatInt(g.Deletions), g.ChangeShare)
+		}
+	}
+
+	listed := changed
+	label := "changed files"
+	if !r.Metadata.Git {
+		listed = r.Files
+		label = "files"
+	}
+	if len(listed) > 0 {
+		fmt.Fprintf(&b, "\n<details>\n<summary>All %s %s</summary>\n\n", formatInt(len(listed)), label)
+		b.WriteString("| File | Status | Lines | Added | Removed |\n")
+		b.WriteString("|---|---|---:|---:|---:|\n")
+		for _, file := range listed {
+			fmt.Fprintf(&b, "| %s | %s | %s | +%s | -%s |\n",
+				markdownPath(file.Path), file.Status, formatInt(file.Lines), formatInt(file.Additions), formatInt(file.Deletions))
+		}
+		b.WriteString("\n</details>\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeMarkdownSummary writes the summary table
+func writeMarkdownSummary(b *strings.Builder, r *Report) {
+	s := r.Summary
+
+	b.WriteString("| | |\n")
+	b.WriteString("|---|---:|\n")
+	if r.Metadata.Git {
+		fmt.Fprintf(b, "| Files changed | %s of %s |\n", formatInt(s.ChangedFiles), formatInt(s.TotalFiles))
+		fmt.Fprintf(b, "| Lines added | +%s |\n", formatInt(s.Additions))
+		fmt.Fprintf(b, "| Lines removed | -%s |\n", formatInt(s.Deletions))
+		fmt.Fprintf(b, "| Net change | %s |\n", formatSigned(s.NetChange))
+		if s.Additions > 0 {
+			fmt.Fprintf(b, "| Lines added to tests / production | +%s / +%s |\n", formatInt(s.TestAdditions), formatInt(s.ProdAdditions))
+		}
+	} else {
+		fmt.Fprintf(b, "| Files | %s |\n", formatInt(s.TotalFiles))
+	}
+	fmt.Fprintf(b, "| Total lines | %s |\n", formatInt(s.TotalLines))
+	if s.Coverage != nil {
+		fmt.Fprintf(b, "| Changed-line coverage | %.1f%% (%s of %s) |\n", s.Coverage.Percent, formatInt(s.Coverage.CoveredLines), formatInt(s.Coverage.ExecutableLines))
+	}
+	if s.Complexity != 0 || s.ComplexityDelta != 0 {
+		fmt.Fprintf(b, "| Complexity | %s (%s) |\n", formatInt(s.Complexity), formatSigned(s.ComplexityDelta))
+	}
+}
+
+// groupingTitle describes a --group-by key for headings, e.g. "dir:2" becomes "directory (depth 2)"
+func groupingTitle(by string) string {
+	kind, depth, _ := strings.Cut(by, ":")
+	switch kind {
+	case "dir":
+		if depth == "1" {
+			return "directory"
+		}
+		return fmt.Sprintf("directory (depth %s)", depth)
+	case "ext":
+		return "extension"
+	case "lang":
+		return "language"
+	default:
+		return kind
+	}
+}
+
+// changedFiles returns the changed files of a report, largest change first
+func changedFiles(r *Report) []File {
+	changed := make([]File, 0, r.Summary.ChangedFiles)
+	for _, file := range r.Files {
+		if file.Changed {
+			changed = append(changed, file)
+		}
+	}
+
+	sort.SliceStable(changed, func(i, j int) bool {
+		return changed[i].Additions+changed[i].Deletions > changed[j].Additions+changed[j].Deletions
+	})
+	return changed
+}
+
+// diffBar renders the ratio of additions to deletions as a row of colored squares
+func diffBar(additions, deletions int) string {
+	total := additions + deletions
+	if total == 0 {
+		return strings.Repeat("⬜", barWidth)
+	}
+
+	green := (additions*barWidth + total/2) / total
+	if additions > 0 && green == 0 {
+		green = 1
+	}
+	if deletions > 0 && green == barWidth {
+		green = barWidth - 1
+	}
+
+	return strings.Repeat("🟩", green) + strings.Repeat("🟥", barWidth-green)
+}
+
+// markdownPath renders a path as inline code, safe for use in a table cell
+func markdownPath(path string) string {
+	return "`" + strings.ReplaceAll(strings.ReplaceAll(path, "`", "'"), "|", `\|`) + "`"
+}
+
+// formatInt formats n with thousands separators, e.g. 12345 becomes "12,345"
+func formatInt(n int) string {
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return sign + b.String()
+}
+
+// formatSigned formats n with thousands separators and an explicit sign
+func formatSigned(n int) string {
+	if n > 0 {
+		return "+" + formatInt(n)
+	}
+	return formatInt(n)
+}
@@ -0,0 +1,58 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, testReport(), MarkdownOptions{Top: 2}); err != nil {
+		t.Fatalf("WriteMarkdown: %v", err)
+	}
+	assertGolden(t, "report.md", buf.Bytes())
+}
+
+func TestWriteMarkdownWithoutGit(t *testing.T) {
+	r := testReport()
+	r.Metadata.Git = false
+	r.Groups = &Grouping{By: "dir:2", Groups: []Group{
+		{Name: "cmd/app", Files: 1, ChangedFiles: 1, Lines: 120, Additions: 12, Deletions: 4, LineShare: 52.2, ChangeShare: 32.7},
+		{Name: "web|src", Files: 1, Lines: 80, LineShare: 34.8},
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, r, MarkdownOptions{}); err != nil {
+		t.Fatalf("WriteMarkdown: %v", err)
+	}
+	assertGolden(t, "report-nogit.md", buf.Bytes())
+}
+
+func TestDiffBar(t *testing.T) {
+	tests := []struct {
+		additions, deletions int
+		want                 string
+	}{
+		{0, 0, "⬜⬜⬜⬜⬜"},
+		{10, 0, "🟩🟩🟩🟩🟩"},
+		{0, 10, "🟥🟥🟥🟥🟥"},
+		{5, 5, "🟩🟩🟩🟥🟥"},
+		{1, 100, "🟩🟥🟥🟥🟥"},
+		{100, 1, "🟩🟩🟩🟩🟥"},
+	}
+
+	for _, tt := range tests {
+		if got := diffBar(tt.additions, tt.deletions); got != tt.want {
+			t.Errorf("diffBar(%d, %d) = %s, want %s", tt.additions, tt.deletions, got, tt.want)
+		}
+	}
+}
+
+func TestFormatInt(t *testing.T) {
+	tests := map[int]string{0: "0", 999: "999", 1000: "1,000", -1234567: "-1,234,567"}
+	for n, want := range tests {
+		if got := formatInt(n); got != want {
+			t.Errorf("formatInt(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+// NDJSON record types
+const (
+	RecordFile    = "file"
+	RecordSummary = "summary"
+)
+
+// FileRecord is a file line of NDJSON output
+type FileRecord struct {
+	Type string `json:"type"`
+	File
+}
+
+// SummaryRecord is the final line of NDJSON output
+type SummaryRecord struct {
+	Type          string      `json:"type"`
+	Schema        string      `json:"schema"`
+	SchemaVersion int         `json:"schema_version"`
+	Metadata      Metadata    `json:"metadata"`
+	Summary       Summary     `json:"summary"`
+	Modules       []Module    `json:"modules,omitempty"`
+	Violations    []Violation `json:"violations,omitempty"`
+}
+
+// NDJSONWriter streams a report as newline-delimited JSON: one file record
+// per line as files are counted, then a single summary record. Files appear
+// in the order they finish, not sorted.
+type NDJSONWriter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	err     error
+}
+
+// NewNDJSONWriter creates a writer that streams records to w
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return &NDJSONWriter{encoder: encoder}
+}
+
+// WriteFile writes one file record. After the first write error further
+// records are dropped; the error is returned by Finish.
+func (n *NDJSONWriter) WriteFile(file *model.FileInfo) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.err != nil {
+		return
+	}
+	n.err = n.encoder.Encode(FileRecord{Type: RecordFile, File: newFile(file)})
+}
+
+// Finish writes the summary record built from the totals in stats
+func (n *NDJSONWriter) Finish(stats *model.Stats, meta Metadata) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.err != nil {
+		return n.err
+	}
+
+	return n.encoder.Encode(SummaryRecord{
+		Type:          RecordSummary,
+		Schema:        Schema,
+		SchemaVersion: SchemaVersion,
+		Metadata:      newMetadata(meta),
+		Summary:       newSummary(stats),
+		Modules:       newModules(stats),
+		Violations:    newViolations(stats.Violations),
+	})
+}
@@ -0,0 +1,60 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNDJSONWriter(t *testing.T) {
+	stats := testStats()
+
+	var buf bytes.Buffer
+	w := NewNDJSONWriter(&buf)
+	for _, file := range stats.ChangedFiles {
+		w.WriteFile(file)
+	}
+	for _, file := range stats.UnchangedFiles {
+		w.WriteFile(file)
+	}
+	if err := w.Finish(stats, testMetadata()); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	assertGolden(t, "report.ndjson", buf.Bytes())
+
+	// Every line is a JSON object with a type; the summary comes last
+	var types []string
+	scanner := bufio.NewScanner(&buf)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		var record struct{ Type string }
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid record %s: %v", scanner.Bytes(), err)
+		}
+		types = append(types, record.Type)
+	}
+	want := []string{RecordFile, RecordFile, RecordFile, RecordFile, RecordSummary}
+	if !reflect.DeepEqual(types, want) {
+		t.Errorf("record types = %v, want %v", types, want)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestNDJSONWriterReportsWriteErrors(t *testing.T) {
+	stats := testStats()
+
+	w := NewNDJSONWriter(failingWriter{})
+	w.WriteFile(stats.ChangedFiles[0])
+	w.WriteFile(stats.ChangedFiles[1])
+	if err := w.Finish(stats, testMetadata()); err == nil || err.Error() != "disk full" {
+		t.Errorf("Finish error = %v, want the write error", err)
+	}
+}
@@ -0,0 +1,136 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// WriteOpenMetrics writes the report as OpenMetrics gauges, suitable for the
+// node_exporter textfile collector. Every sample carries the repo and ref
+// labels; line and file counts are further broken down by language and
+// top-level directory, and by group when the report has groups. Samples have
+// no timestamp, as the textfile collector
+// rejects them.
+func WriteOpenMetrics(w io.Writer, r *Report) error {
+	var b strings.Builder
+
+	base := []label{{"repo", metricsRepo(r)}, {"ref", metricsRef(r)}}
+
+	type bucket struct{ lang, dir string }
+	lines := make(map[bucket]int)
+	files := make(map[bucket]int)
+	for _, file := range r.Files {
+		key := bucket{lang: file.Language, dir: topLevelDir(file.Path)}
+		lines[key] += file.Lines
+		files[key]++
+	}
+
+	keys := make([]bucket, 0, len(lines))
+	for key := range lines {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].lang != keys[j].lang {
+			return keys[i].lang < keys[j].lang
+		}
+		return keys[i].dir < keys[j].dir
+	})
+
+	writeMetricHeader(&b, "diffloc_lines_total", "Lines of code by language and top-level directory.")
+	for _, key := range keys {
+		writeSample(&b, "diffloc_lines_total", append(base, label{"lang", key.lang}, label{"dir", key.dir}), lines[key])
+	}
+
+	writeMetricHeader(&b, "diffloc_files", "Files by language and top-level directory.")
+	for _, key := range keys {
+		writeSample(&b, "diffloc_files", append(base, label{"lang", key.lang}, label{"dir", key.dir}), files[key])
+	}
+
+	if r.Groups != nil {
+		groupMetrics := []struct {
+			name  string
+			help  string
+			value func(g Group) int
+		}{
+			{"diffloc_group_lines_total", "Lines of code by group.", func(g Group) int { return g.Lines }},
+			{"diffloc_group_files", "Files by group.", func(g Group) int { return g.Files }},
+			{"diffloc_group_additions_total", "Lines added by group.", func(g Group) int { return g.Additions }},
+			{"diffloc_group_deletions_total", "Lines removed by group.", func(g Group) int { return g.Deletions }},
+		}
+		for _, metric := range groupMetrics {
+			writeMetricHeader(&b, metric.name, metric.help)
+			for _, g := range r.Groups.Groups {
+				writeSample(&b, metric.name, append(base, label{"group_by", r.Groups.By}, label{"group", g.Name}), metric.value(g))
+			}
+		}
+	}
+
+	totals := []struct {
+		name  string
+		help  string
+		value int
+	}{
+		{"diffloc_additions_total", "Lines added in the working tree compared to the base ref.", r.Summary.Additions},
+		{"diffloc_deletions_total", "Lines removed in the working tree compared to the base ref.", r.Summary.Deletions},
+		{"diffloc_net_change", "Lines added minus lines removed.", r.Summary.NetChange},
+		{"diffloc_changed_files", "Files changed compared to the base ref.", r.Summary.ChangedFiles},
+	}
+	for _, total := range totals {
+		writeMetricHeader(&b, total.name, total.help)
+		writeSample(&b, total.name, base, total.value)
+	}
+
+	b.WriteString("# EOF\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type label struct{ name, value string }
+
+func writeMetricHeader(b *strings.Builder, name, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s gauge\n", name)
+}
+
+func writeSample(b *strings.Builder, name string, labels []label, value int) {
+	b.WriteString(name)
+	b.WriteByte('{')
+	for i, l := range labels {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(b, "%s=\"%s\"", l.name, escapeLabelValue(l.value))
+	}
+	fmt.Fprintf(b, "} %d\n", value)
+}
+
+// escapeLabelValue escapes backslashes, double quotes and newlines in a label value
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+// metricsRepo returns the repository name used in the repo label
+func metricsRepo(r *Report) string {
+	return path.Base(strings.ReplaceAll(r.Metadata.RepoRoot, `\`, "/"))
+}
+
+// metricsRef returns the branch, or the short HEAD SHA on a detached HEAD
+func metricsRef(r *Report) string {
+	if r.Metadata.Branch != "" {
+		return r.Metadata.Branch
+	}
+	return shortSHA(r.Metadata.HeadSHA)
+}
+
+// topLevelDir returns the first directory component of a slash-separated path, or "." for files at the root
+func topLevelDir(p string) string {
+	dir, _, found := strings.Cut(p, "/")
+	if !found {
+		return "."
+	}
+	return dir
+}
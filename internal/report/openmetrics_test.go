@@ -0,0 +1,45 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteOpenMetrics(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteOpenMetrics(&buf, testReport()); err != nil {
+		t.Fatalf("WriteOpenMetrics: %v", err)
+	}
+	assertGolden(t, "report.om", buf.Bytes())
+
+	if !strings.HasSuffix(buf.String(), "\n# EOF\n") {
+		t.Error("OpenMetrics output does not end with # EOF")
+	}
+}
+
+func TestWriteOpenMetricsLabels(t *testing.T) {
+	r := testReport()
+	r.Metadata.RepoRoot = `C:\src\my repo`
+	r.Metadata.Branch = ""
+	r.Files = []File{{Path: "we\"ird\\dir\nname/a.go", Language: "Go", Lines: 3}}
+
+	var buf bytes.Buffer
+	if err := WriteOpenMetrics(&buf, r); err != nil {
+		t.Fatalf("WriteOpenMetrics: %v", err)
+	}
+
+	// Detached HEAD falls back to the short SHA; label values are escaped
+	want := `diffloc_lines_total{repo="my repo",ref="0123456789ab",lang="Go",dir="we\"ird\\dir\nname"} 3` + "\n"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("OpenMetrics output lacks %q:\n%s", want, buf.String())
+	}
+}
+
+func TestWriteOpenMetricsGroups(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteOpenMetrics(&buf, testGroupedReport()); err != nil {
+		t.Fatalf("WriteOpenMetrics: %v", err)
+	}
+	assertGolden(t, "report-groups.om", buf.Bytes())
+}
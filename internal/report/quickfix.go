@@ -0,0 +1,74 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+// quickfixEntry is one hunk of a changed file
+type quickfixEntry struct {
+	path string
+	hunk Hunk
+}
+
+// WriteQuickfix writes one "path:line:col: +N -M summary" line per changed
+// hunk, largest first, in the errorformat understood by Vim's quickfix list,
+// Emacs compilation-mode and VS Code problem matchers. Deleted files are
+// skipped since there is nothing to jump to.
+func WriteQuickfix(w io.Writer, r *Report) error {
+	var entries []quickfixEntry
+	for _, file := range r.Files {
+		if file.Status == model.StatusDeleted {
+			continue
+		}
+		for _, hunk := range file.Hunks {
+			entries = append(entries, quickfixEntry{path: file.Path, hunk: hunk})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		si := entries[i].hunk.OldLines + entries[i].hunk.NewLines
+		sj := entries[j].hunk.OldLines + entries[j].hunk.NewLines
+		return si > sj
+	})
+
+	bw := bufio.NewWriter(w)
+	for _, e := range entries {
+		fmt.Fprintf(bw, "%s:%d:1: +%d -%d %s\n", e.path, max(e.hunk.NewStart, 1), e.hunk.NewLines, e.hunk.OldLines, quickfixSummary(e.hunk))
+	}
+	return bw.Flush()
+}
+
+// quickfixSummary describes a hunk, e.g. "changed lines 4-5" or "removed 4 lines after line 13"
+func quickfixSummary(h Hunk) string {
+	switch {
+	case h.NewLines == 0 && h.NewStart == 0:
+		return fmt.Sprintf("removed %s at the start of the file", pluralLines(h.OldLines))
+	case h.NewLines == 0:
+		return fmt.Sprintf("removed %s after line %d", pluralLines(h.OldLines), h.NewStart)
+	case h.OldLines == 0:
+		return "added " + lineSpan(h.NewStart, h.NewLines)
+	default:
+		return "changed " + lineSpan(h.NewStart, h.NewLines)
+	}
+}
+
+// lineSpan formats a range of lines such as "line 4" or "lines 4-5"
+func lineSpan(start, n int) string {
+	if n == 1 {
+		return fmt.Sprintf("line %d", start)
+	}
+	return fmt.Sprintf("lines %d-%d", start, start+n-1)
+}
+
+// pluralLines formats a line count with the right plural
+func pluralLines(n int) string {
+	if n == 1 {
+		return "1 line"
+	}
+	return fmt.Sprintf("%d lines", n)
+}
@@ -0,0 +1,36 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteQuickfix(t *testing.T) {
+	r := testReport()
+	r.Files = append(r.Files, File{
+		Path:   "web/src/util.js",
+		Status: "modified",
+		Hunks: []Hunk{
+			{OldStart: 1, OldLines: 2, NewStart: 0, NewLines: 0, Removed: []int{1, 2}},
+			{OldStart: 20, OldLines: 0, NewStart: 18, NewLines: 1, Added: []int{18}},
+			{OldStart: 30, OldLines: 4, NewStart: 28, NewLines: 0, Removed: []int{30, 31, 32, 33}},
+		},
+	})
+
+	var buf bytes.Buffer
+	if err := WriteQuickfix(&buf, r); err != nil {
+		t.Fatalf("WriteQuickfix: %v", err)
+	}
+
+	// Largest hunks first, ties in report order; deleted files are skipped and
+	// removals at the top of a file point at line 1
+	want := "internal/calc/calc_test.go:1:1: +30 -0 added lines 1-30\n" +
+		"cmd/app/main.go:10:1: +8 -2 changed lines 10-17\n" +
+		"cmd/app/main.go:46:1: +4 -2 changed lines 46-49\n" +
+		"web/src/util.js:28:1: +0 -4 removed 4 lines after line 28\n" +
+		"web/src/util.js:1:1: +0 -2 removed 2 lines at the start of the file\n" +
+		"web/src/util.js:18:1: +1 -0 added line 18\n"
+	if buf.String() != want {
+		t.Errorf("WriteQuickfix =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
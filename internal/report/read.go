@@ -0,0 +1,166 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+// Read decodes a JSON report written by WriteJSON, rejecting other documents
+// and unsupported schema versions. Unknown fields are ignored so that reports
+// from newer minor releases still load.
+func Read(r io.Reader) (*Report, error) {
+	var report Report
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return nil, fmt.Errorf("decoding report: %w", err)
+	}
+
+	if report.Schema != Schema {
+		return nil, fmt.Errorf("not a diffloc report (schema %q)", report.Schema)
+	}
+	if report.SchemaVersion != SchemaVersion {
+		return nil, fmt.Errorf("unsupported report schema version %d (expected %d)", report.SchemaVersion, SchemaVersion)
+	}
+	return &report, nil
+}
+
+// ReadFile reads a JSON report from a file, or from stdin when name is "-"
+func ReadFile(name string) (*Report, error) {
+	if name == "-" {
+		return Read(os.Stdin)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	report, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return report, nil
+}
+
+// Stats converts a report back into analysis results, as the UI renders them
+func (r *Report) Stats() *model.Stats {
+	s := r.Summary
+	stats := &model.Stats{
+		ChangedFiles:    make([]*model.FileInfo, 0, s.ChangedFiles),
+		UnchangedFiles:  make([]*model.FileInfo, 0, s.UnchangedFiles),
+		TotalFiles:      s.TotalFiles,
+		ChangedCount:    s.ChangedFiles,
+		UnchangedCount:  s.UnchangedFiles,
+		TotalLines:      s.TotalLines,
+		TotalAdditions:  s.Additions,
+		TotalDeletions:  s.Deletions,
+		NetChange:       s.NetChange,
+		TestLines:       s.TestLines,
+		TestAdditions:   s.TestAdditions,
+		ProdLines:       s.ProdLines,
+		ProdAdditions:   s.ProdAdditions,
+		Coverage:        s.Coverage.model(),
+		TotalComplexity: s.Complexity,
+		ComplexityDelta: s.ComplexityDelta,
+	}
+
+	for _, f := range r.Files {
+		file := f.model()
+		if file.IsChanged {
+			stats.ChangedFiles = append(stats.ChangedFiles, file)
+		} else {
+			stats.UnchangedFiles = append(stats.UnchangedFiles, file)
+		}
+	}
+
+	if r.Groups != nil {
+		stats.GroupBy = r.Groups.By
+		for _, g := range r.Groups.Groups {
+			stats.Groups = append(stats.Groups, &model.Group{
+				Name:         g.Name,
+				Files:        g.Files,
+				ChangedFiles: g.ChangedFiles,
+				Lines:        g.Lines,
+				Additions:    g.Additions,
+				Deletions:    g.Deletions,
+				LineShare:    g.LineShare,
+				ChangeShare:  g.ChangeShare,
+			})
+		}
+	}
+
+	for _, mod := range r.Modules {
+		stats.Modules = append(stats.Modules, &model.Module{Name: mod.Name, Path: mod.Path, Kind: mod.Kind})
+	}
+
+	for _, v := range r.Violations {
+		stats.Violations = append(stats.Violations, &model.Violation{
+			Rule:    v.Rule,
+			Scope:   v.Scope,
+			Path:    v.Path,
+			Message: v.Message,
+			Limit:   v.Limit,
+			Actual:  v.Actual,
+		})
+	}
+
+	return stats
+}
+
+// model converts a report file back into a model.FileInfo
+func (f File) model() *model.FileInfo {
+	file := &model.FileInfo{
+		Path:            f.Path,
+		Lines:           f.Lines,
+		Additions:       f.Additions,
+		Deletions:       f.Deletions,
+		IsChanged:       f.Changed,
+		IsTest:          f.Test,
+		Status:          f.Status,
+		Complexity:      f.Complexity,
+		ComplexityDelta: f.ComplexityDelta,
+		Module:          f.Module,
+		Owners:          f.Owners,
+		Coverage:        f.Coverage.model(),
+	}
+
+	for _, sym := range f.Symbols {
+		file.Symbols = append(file.Symbols, &model.SymbolChange{
+			Package:   sym.Package,
+			Kind:      sym.Kind,
+			Name:      sym.Name,
+			Change:    sym.Change,
+			Additions: sym.Additions,
+			Deletions: sym.Deletions,
+		})
+	}
+
+	for _, hunk := range f.Hunks {
+		file.Hunks = append(file.Hunks, &model.Hunk{
+			OldStart: hunk.OldStart,
+			OldLines: hunk.OldLines,
+			NewStart: hunk.NewStart,
+			NewLines: hunk.NewLines,
+			Added:    hunk.Added,
+			Removed:  hunk.Removed,
+		})
+	}
+
+	return file
+}
+
+// model converts report coverage back into model.Coverage
+func (c *Coverage) model() *model.Coverage {
+	if c == nil {
+		return nil
+	}
+	return &model.Coverage{
+		Executable: c.ExecutableLines,
+		Covered:    c.CoveredLines,
+		Uncovered:  c.UncoveredLines,
+	}
+}
@@ -0,0 +1,334 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/nodelike/diffloc/internal/analyzer"
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+// Schema identifies diffloc JSON reports
+const Schema = "diffloc/report"
+
+// SchemaVersion is the major version of the report format. It is bumped
+// whenever a field is removed, renamed or changes meaning; new optional
+// fields may be added within a version.
+const SchemaVersion = 1
+
+// Report is the stable, versioned JSON representation of an analysis
+type Report struct {
+	Schema        string      `json:"schema"`
+	SchemaVersion int         `json:"schema_version"`
+	Metadata      Metadata    `json:"metadata"`
+	Summary       Summary     `json:"summary"`
+	Files         []File      `json:"files"`
+	Groups        *Grouping   `json:"groups,omitempty"`
+	Modules       []Module    `json:"modules,omitempty"`
+	Violations    []Violation `json:"violations,omitempty"`
+}
+
+// Metadata describes where and how a report was produced
+type Metadata struct {
+	Tool        string    `json:"tool"`
+	ToolVersion string    `json:"tool_version"`
+	GeneratedAt time.Time `json:"generated_at"`
+	RepoRoot    string    `json:"repo_root"`
+	Git         bool      `json:"git"`
+	BaseRef     string    `json:"base_ref,omitempty"`
+	HeadSHA     string    `json:"head_sha,omitempty"`
+	Branch      string    `json:"branch,omitempty"`
+	Filter      Filter    `json:"filter"`
+	Options     Options   `json:"options"`
+}
+
+// Filter records the file selection settings of a run
+type Filter struct {
+	Extensions       []string `json:"extensions"`
+	Excludes         []string `json:"excludes"`
+	ExcludeTests     bool     `json:"exclude_tests"`
+	RespectGitignore bool     `json:"respect_gitignore"`
+	MaxDepth         int      `json:"max_depth"`
+}
+
+// Options records the optional analysis passes enabled for a run
+type Options struct {
+	GoSymbols       bool   `json:"go_symbols"`
+	IgnoreGofmt     bool   `json:"ignore_gofmt"`
+	Complexity      bool   `json:"complexity"`
+	GroupBy         string `json:"group_by,omitempty"`
+	AffectedModules bool   `json:"affected_modules"`
+	Hunks           bool   `json:"hunks,omitempty"`
+}
+
+// Summary holds the aggregate totals of a report
+type Summary struct {
+	TotalFiles      int       `json:"total_files"`
+	ChangedFiles    int       `json:"changed_files"`
+	UnchangedFiles  int       `json:"unchanged_files"`
+	TotalLines      int       `json:"total_lines"`
+	Additions       int       `json:"additions"`
+	Deletions       int       `json:"deletions"`
+	NetChange       int       `json:"net_change"`
+	TestLines       int       `json:"test_lines,omitempty"`
+	TestAdditions   int       `json:"test_additions,omitempty"`
+	ProdLines       int       `json:"prod_lines,omitempty"`
+	ProdAdditions   int       `json:"prod_additions,omitempty"`
+	Complexity      int       `json:"complexity,omitempty"`
+	ComplexityDelta int       `json:"complexity_delta,omitempty"`
+	Coverage        *Coverage `json:"coverage,omitempty"`
+}
+
+// File holds the statistics of a single file
+type File struct {
+	Path            string    `json:"path"`
+	Language        string    `json:"language"`
+	Status          string    `json:"status,omitempty"`
+	Changed         bool      `json:"changed"`
+	Test            bool      `json:"test,omitempty"`
+	Lines           int       `json:"lines"`
+	Additions       int       `json:"additions"`
+	Deletions       int       `json:"deletions"`
+	Complexity      int       `json:"complexity,omitempty"`
+	ComplexityDelta int       `json:"complexity_delta,omitempty"`
+	Module          string    `json:"module,omitempty"`
+	Owners          []string  `json:"owners,omitempty"`
+	Symbols         []Symbol  `json:"symbols,omitempty"`
+	Coverage        *Coverage `json:"coverage,omitempty"`
+	Hunks           []Hunk    `json:"hunks,omitempty"`
+}
+
+// Hunk is a run of changed lines, like a unified diff hunk without context.
+// A side without lines starts at the line after which the change happened.
+type Hunk struct {
+	OldStart int   `json:"old_start"`
+	OldLines int   `json:"old_lines"`
+	NewStart int   `json:"new_start"`
+	NewLines int   `json:"new_lines"`
+	Added    []int `json:"added,omitempty"`
+	Removed  []int `json:"removed,omitempty"`
+}
+
+// Coverage is the test coverage of the executable lines added by a change
+type Coverage struct {
+	ExecutableLines int     `json:"executable_lines"`
+	CoveredLines    int     `json:"covered_lines"`
+	Percent         float64 `json:"percent"`
+	UncoveredLines  []int   `json:"uncovered_lines,omitempty"`
+}
+
+// newCoverage converts coverage into its report representation, rounding the percentage to one decimal
+func newCoverage(c *model.Coverage) *Coverage {
+	if c == nil {
+		return nil
+	}
+	return &Coverage{
+		ExecutableLines: c.Executable,
+		CoveredLines:    c.Covered,
+		Percent:         float64(int(c.Percent()*10+0.5)) / 10,
+		UncoveredLines:  c.Uncovered,
+	}
+}
+
+// Symbol is a top-level Go declaration touched by a change
+type Symbol struct {
+	Package   string `json:"package"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Change    string `json:"change"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+// Grouping holds the rollups requested with --group-by
+type Grouping struct {
+	By     string  `json:"by"`
+	Groups []Group `json:"groups"`
+}
+
+// Group holds the aggregate statistics of a bucket of files
+type Group struct {
+	Name         string  `json:"name"`
+	Files        int     `json:"files"`
+	ChangedFiles int     `json:"changed_files"`
+	Lines        int     `json:"lines"`
+	Additions    int     `json:"additions"`
+	Deletions    int     `json:"deletions"`
+	LineShare    float64 `json:"line_share"`
+	ChangeShare  float64 `json:"change_share"`
+}
+
+// Module is a module boundary detected in the analyzed tree
+type Module struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+}
+
+// Violation is a size budget or policy rule broken by the analyzed files
+type Violation struct {
+	Rule    string `json:"rule"`
+	Scope   string `json:"scope,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+	Limit   int    `json:"limit"`
+	Actual  int    `json:"actual"`
+}
+
+// newViolations converts violations into their report representation
+func newViolations(violations []*model.Violation) []Violation {
+	var result []Violation
+	for _, v := range violations {
+		result = append(result, Violation{
+			Rule:    v.Rule,
+			Scope:   v.Scope,
+			Path:    filepath.ToSlash(v.Path),
+			Message: v.Message,
+			Limit:   v.Limit,
+			Actual:  v.Actual,
+		})
+	}
+	return result
+}
+
+// New builds a report from analysis results. Files are ordered by path and
+// groups by name so that identical inputs always produce identical output.
+func New(stats *model.Stats, meta Metadata) *Report {
+	r := &Report{
+		Schema:        Schema,
+		SchemaVersion: SchemaVersion,
+		Metadata:      newMetadata(meta),
+		Summary:       newSummary(stats),
+		Files:         make([]File, 0, stats.TotalFiles),
+		Modules:       newModules(stats),
+		Violations:    newViolations(stats.Violations),
+	}
+
+	for _, file := range stats.ChangedFiles {
+		r.Files = append(r.Files, newFile(file))
+	}
+	for _, file := range stats.UnchangedFiles {
+		r.Files = append(r.Files, newFile(file))
+	}
+	sort.Slice(r.Files, func(i, j int) bool {
+		return r.Files[i].Path < r.Files[j].Path
+	})
+
+	if stats.GroupBy != "" {
+		r.Groups = &Grouping{By: stats.GroupBy, Groups: make([]Group, 0, len(stats.Groups))}
+		for _, g := range stats.Groups {
+			r.Groups.Groups = append(r.Groups.Groups, Group{
+				Name:         g.Name,
+				Files:        g.Files,
+				ChangedFiles: g.ChangedFiles,
+				Lines:        g.Lines,
+				Additions:    g.Additions,
+				Deletions:    g.Deletions,
+				LineShare:    g.LineShare,
+				ChangeShare:  g.ChangeShare,
+			})
+		}
+		sort.Slice(r.Groups.Groups, func(i, j int) bool {
+			return r.Groups.Groups[i].Name < r.Groups.Groups[j].Name
+		})
+	}
+
+	return r
+}
+
+// newMetadata fills in the defaults of run metadata
+func newMetadata(meta Metadata) Metadata {
+	if meta.Tool == "" {
+		meta.Tool = "diffloc"
+	}
+	if meta.Filter.Extensions == nil {
+		meta.Filter.Extensions = []string{}
+	}
+	if meta.Filter.Excludes == nil {
+		meta.Filter.Excludes = []string{}
+	}
+	return meta
+}
+
+// newSummary converts the totals of stats
+func newSummary(stats *model.Stats) Summary {
+	return Summary{
+		TotalFiles:      stats.TotalFiles,
+		ChangedFiles:    stats.ChangedCount,
+		UnchangedFiles:  stats.UnchangedCount,
+		TotalLines:      stats.TotalLines,
+		Additions:       stats.TotalAdditions,
+		Deletions:       stats.TotalDeletions,
+		NetChange:       stats.NetChange,
+		TestLines:       stats.TestLines,
+		TestAdditions:   stats.TestAdditions,
+		ProdLines:       stats.ProdLines,
+		ProdAdditions:   stats.ProdAdditions,
+		Complexity:      stats.TotalComplexity,
+		ComplexityDelta: stats.ComplexityDelta,
+		Coverage:        newCoverage(stats.Coverage),
+	}
+}
+
+// newModules converts the detected modules of stats
+func newModules(stats *model.Stats) []Module {
+	var modules []Module
+	for _, mod := range stats.Modules {
+		modules = append(modules, Module{Name: mod.Name, Path: mod.Path, Kind: mod.Kind})
+	}
+	return modules
+}
+
+// newFile converts a model.FileInfo into its report representation
+func newFile(file *model.FileInfo) File {
+	f := File{
+		Path:            filepath.ToSlash(file.Path),
+		Language:        analyzer.Language(file.Path),
+		Status:          file.Status,
+		Changed:         file.IsChanged,
+		Test:            file.IsTest,
+		Lines:           file.Lines,
+		Additions:       file.Additions,
+		Deletions:       file.Deletions,
+		Complexity:      file.Complexity,
+		ComplexityDelta: file.ComplexityDelta,
+		Module:          file.Module,
+		Owners:          file.Owners,
+		Coverage:        newCoverage(file.Coverage),
+	}
+
+	for _, hunk := range file.Hunks {
+		f.Hunks = append(f.Hunks, Hunk{
+			OldStart: hunk.OldStart,
+			OldLines: hunk.OldLines,
+			NewStart: hunk.NewStart,
+			NewLines: hunk.NewLines,
+			Added:    hunk.Added,
+			Removed:  hunk.Removed,
+		})
+	}
+
+	for _, sym := range file.Symbols {
+		f.Symbols = append(f.Symbols, Symbol{
+			Package:   sym.Package,
+			Kind:      sym.Kind,
+			Name:      sym.Name,
+			Change:    sym.Change,
+			Additions: sym.Additions,
+			Deletions: sym.Deletions,
+		})
+	}
+
+	return f
+}
+
+// WriteJSON writes the report as indented JSON
+func WriteJSON(w io.Writer, r *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(r)
+}
@@ -0,0 +1,267 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// testStats is a small analysis with a file of every status
+func testStats() *model.Stats {
+	return &model.Stats{
+		ChangedFiles: []*model.FileInfo{
+			{
+				Path: "cmd/app/main.go", Lines: 120, Additions: 12, Deletions: 4, IsChanged: true,
+				Status: model.StatusModified, Module: ".", Owners: []string{"@core"},
+				Symbols: []*model.SymbolChange{
+					{Package: "main", Kind: model.SymbolFunc, Name: "run", Change: model.SymbolModified, Additions: 8, Deletions: 2},
+				},
+				Hunks: []*model.Hunk{
+					{OldStart: 10, OldLines: 2, NewStart: 10, NewLines: 8, Added: []int{10, 11, 12, 13, 14, 15, 16, 17}, Removed: []int{10, 11}},
+					{OldStart: 40, OldLines: 2, NewStart: 46, NewLines: 4, Added: []int{46, 47, 48, 49}, Removed: []int{40, 41}},
+				},
+				Coverage: &model.Coverage{Executable: 8, Covered: 6, Uncovered: []int{12, 13}},
+			},
+			{
+				Path: "internal/calc/calc_test.go", Lines: 30, Additions: 30, IsChanged: true, IsTest: true,
+				Status: model.StatusAdded, Module: ".",
+				Hunks: []*model.Hunk{{NewStart: 1, NewLines: 30, Added: lineRange(1, 30)}},
+			},
+			{
+				Path: "scripts/old.py", Deletions: 3, IsChanged: true, Status: model.StatusDeleted,
+				Hunks: []*model.Hunk{{OldStart: 1, OldLines: 3, Removed: []int{1, 2, 3}}},
+			},
+		},
+		UnchangedFiles: []*model.FileInfo{
+			{Path: "web/src/app.js", Lines: 80, Status: model.StatusUnchanged},
+		},
+		TotalFiles:     4,
+		ChangedCount:   3,
+		UnchangedCount: 1,
+		TotalLines:     230,
+		TotalAdditions: 42,
+		TotalDeletions: 7,
+		NetChange:      35,
+		TestLines:      30,
+		TestAdditions:  30,
+		ProdLines:      200,
+		ProdAdditions:  12,
+		Coverage:       &model.Coverage{Executable: 8, Covered: 6, Uncovered: nil},
+		Modules:        []*model.Module{{Name: "example.com/app", Path: ".", Kind: "go"}},
+		Violations: []*model.Violation{
+			{Rule: "max-additions", Message: "42 lines added, budget is 40", Limit: 40, Actual: 42},
+			{Rule: "max-changed-files", Scope: "scripts/", Message: "scripts/: 1 file changed, budget is 0", Limit: 0, Actual: 1},
+			{Rule: "max-file-lines", Path: "cmd/app/main.go", Message: "cmd/app/main.go has 120 lines, limit is 100", Limit: 100, Actual: 120},
+		},
+	}
+}
+
+// testMetadata describes a fixed run so that output does not depend on the clock
+func testMetadata() Metadata {
+	return Metadata{
+		ToolVersion: "1.2.3",
+		GeneratedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		RepoRoot:    "/work/app",
+		Git:         true,
+		BaseRef:     "HEAD",
+		HeadSHA:     "0123456789abcdef0123456789abcdef01234567",
+		Branch:      "main",
+		Filter:      Filter{RespectGitignore: true},
+		Options:     Options{Hunks: true},
+	}
+}
+
+func testReport() *Report {
+	return New(testStats(), testMetadata())
+}
+
+// testGroupedReport is testReport rolled up with --group-by dir
+func testGroupedReport() *Report {
+	stats := testStats()
+	stats.GroupBy = "dir:1"
+	stats.Groups = []*model.Group{
+		{Name: "cmd", Files: 1, ChangedFiles: 1, Lines: 120, Additions: 12, Deletions: 4, LineShare: 52.2, ChangeShare: 32.7},
+		{Name: "web", Files: 1, Lines: 80, LineShare: 34.8},
+		{Name: "internal", Files: 1, ChangedFiles: 1, Lines: 30, Additions: 30, LineShare: 13, ChangeShare: 61.2},
+		{Name: "scripts", Files: 1, ChangedFiles: 1, Deletions: 3, ChangeShare: 6.1},
+	}
+	return New(stats, testMetadata())
+}
+
+func lineRange(first, last int) []int {
+	var lines []int
+	for l := first; l <= last; l++ {
+		lines = append(lines, l)
+	}
+	return lines
+}
+
+// assertGolden compares got with testdata/name; go test -update rewrites the file
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	file := filepath.Join("testdata", name)
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s:\n%s", file, got)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, testReport()); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+	assertGolden(t, "report.json", buf.Bytes())
+}
+
+func TestReadRoundTrip(t *testing.T) {
+	want := testReport()
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, want); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+	got, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read(WriteJSON(r)) differs from r:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestReadRejectsOtherDocuments(t *testing.T) {
+	for _, doc := range []string{
+		`{"schema": "something/else", "schema_version": 1}`,
+		`{"schema": "diffloc/report", "schema_version": 2}`,
+		`[1, 2, 3]`,
+	} {
+		if _, err := Read(strings.NewReader(doc)); err == nil {
+			t.Errorf("Read(%s) succeeded, want an error", doc)
+		}
+	}
+}
+
+// TestJSONMatchesSchema checks the report against the published schema:
+// required fields, documented field names, types, enums and constants
+func TestJSONMatchesSchema(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, testGroupedReport()); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+
+	var schema, doc map[string]any
+	if err := json.Unmarshal(JSONSchema, &schema); err != nil {
+		t.Fatalf("invalid schema: %v", err)
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid report: %v", err)
+	}
+
+	for _, problem := range checkSchema(schema, schema, doc, "") {
+		t.Error(problem)
+	}
+}
+
+// checkSchema validates value against the subset of JSON Schema used by
+// report.v1.schema.json and returns every mismatch
+func checkSchema(root, schema map[string]any, value any, at string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		def := strings.TrimPrefix(ref, "#/$defs/")
+		return checkSchema(root, root["$defs"].(map[string]any)[def].(map[string]any), value, at)
+	}
+
+	var problems []string
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, value) {
+		problems = append(problems, fmt.Sprintf("%s: %v is not the constant %v", at, value, c))
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			found = found || reflect.DeepEqual(e, value)
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("%s: %v is not one of %v", at, value, enum))
+		}
+	}
+
+	switch schema["type"] {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return append(problems, fmt.Sprintf("%s: %v is not an object", at, value))
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, ok := obj[name.(string)]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: missing required %s", at, name))
+			}
+		}
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, ok := properties[name].(map[string]any)
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s: %s is not documented", at, name))
+				continue
+			}
+			problems = append(problems, checkSchema(root, property, obj[name], at+"/"+name)...)
+		}
+	case "array":
+		list, ok := value.([]any)
+		if !ok {
+			return append(problems, fmt.Sprintf("%s: %v is not an array", at, value))
+		}
+		items, _ := schema["items"].(map[string]any)
+		for i, item := range list {
+			problems = append(problems, checkSchema(root, items, item, fmt.Sprintf("%s/%d", at, i))...)
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			problems = append(problems, fmt.Sprintf("%s: %v is not a string", at, value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			problems = append(problems, fmt.Sprintf("%s: %v is not a boolean", at, value))
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != float64(int64(n)) {
+			problems = append(problems, fmt.Sprintf("%s: %v is not an integer", at, value))
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			problems = append(problems, fmt.Sprintf("%s: %v is not a number", at, value))
+		}
+	}
+	return problems
+}
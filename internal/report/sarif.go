@@ -0,0 +1,192 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/nodelike/diffloc/internal/policy"
+)
+
+// SARIFVersion is the SARIF specification version written by WriteSARIF
+const SARIFVersion = "2.1.0"
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// sarifRoot is the uriBaseId artifact locations are relative to
+const sarifRoot = "SRCROOT"
+
+// SARIFOptions controls the SARIF output
+type SARIFOptions struct {
+	InformationURI string // Home page of the tool
+	ConfigPath     string // Repository-relative config file that budget results point at; empty omits their location
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string          `json:"ruleId"`
+	RuleIndex  int             `json:"ruleIndex"`
+	Level      string          `json:"level"`
+	Message    sarifMessage    `json:"message"`
+	Locations  []sarifLocation `json:"locations,omitempty"`
+	Properties sarifProperties `json:"properties"`
+}
+
+type sarifProperties struct {
+	Limit  int    `json:"limit"`
+	Actual int    `json:"actual"`
+	Scope  string `json:"scope,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// WriteSARIF writes the budget and rule violations of r as a SARIF 2.1.0
+// log with a single run. File rule results point at the offending file;
+// budget results point at the config file when opts.ConfigPath is set.
+func WriteSARIF(w io.Writer, r *Report, opts SARIFOptions) error {
+	rules := make([]sarifRule, len(policy.Rules))
+	ruleIndex := make(map[string]int, len(policy.Rules))
+	for i, rule := range policy.Rules {
+		rules[i] = sarifRule{
+			ID:                   rule.ID,
+			Name:                 rule.Name,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: rule.Level},
+		}
+		ruleIndex[rule.ID] = i
+	}
+
+	results := make([]sarifResult, 0, len(r.Violations))
+	for _, v := range r.Violations {
+		index, ok := ruleIndex[v.Rule]
+		if !ok {
+			continue
+		}
+
+		result := sarifResult{
+			RuleID:     v.Rule,
+			RuleIndex:  index,
+			Level:      policy.Rules[index].Level,
+			Message:    sarifMessage{Text: v.Message},
+			Properties: sarifProperties{Limit: v.Limit, Actual: v.Actual, Scope: v.Scope},
+		}
+		if location := sarifLocationOf(v, opts); location != nil {
+			result.Locations = []sarifLocation{*location}
+		}
+		results = append(results, result)
+	}
+
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           r.Metadata.Tool,
+			Version:        r.Metadata.ToolVersion,
+			InformationURI: opts.InformationURI,
+			Rules:          rules,
+		}},
+		Results: results,
+	}
+	if r.Metadata.RepoRoot != "" {
+		run.OriginalURIBaseIDs = map[string]sarifArtifactLocation{
+			sarifRoot: {URI: fileURI(r.Metadata.RepoRoot)},
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: SARIFVersion,
+		Runs:    []sarifRun{run},
+	})
+}
+
+// sarifLocationOf returns where a violation should be reported, or nil if it has no location
+func sarifLocationOf(v Violation, opts SARIFOptions) *sarifLocation {
+	path, line := v.Path, 1
+	switch {
+	case path == "" && opts.ConfigPath == "":
+		return nil
+	case path == "":
+		path = opts.ConfigPath
+	case v.Rule == policy.RuleMaxFileLines:
+		// Point at the first line past the limit
+		line = v.Limit + 1
+	}
+
+	return &sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: relativeURI(path), URIBaseID: sarifRoot},
+		Region:           &sarifRegion{StartLine: line},
+	}}
+}
+
+// relativeURI escapes a slash-separated relative path for use as a URI reference
+func relativeURI(path string) string {
+	return (&url.URL{Path: filepath.ToSlash(path)}).EscapedPath()
+}
+
+// fileURI returns the file:// URI of a directory, with the trailing slash SARIF requires for base IDs
+func fileURI(dir string) string {
+	p := filepath.ToSlash(dir)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	if !strings.HasSuffix(p, "/") {
+		p += "/"
+	}
+	return (&url.URL{Scheme: "file", Path: p}).String()
+}
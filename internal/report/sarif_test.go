@@ -0,0 +1,90 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/nodelike/diffloc/internal/policy"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	opts := SARIFOptions{InformationURI: "https://github.com/nodelike/diffloc", ConfigPath: ".diffloc.yaml"}
+	if err := WriteSARIF(&buf, testReport(), opts); err != nil {
+		t.Fatalf("WriteSARIF: %v", err)
+	}
+	assertGolden(t, "report.sarif", buf.Bytes())
+
+	log := decodeSARIF(t, buf.Bytes())
+	run := log.Runs[0]
+	if got := run.OriginalURIBaseIDs[sarifRoot].URI; got != "file:///work/app/" {
+		t.Errorf("%s base URI = %q, want file:///work/app/", sarifRoot, got)
+	}
+
+	// Rules are listed in policy.Rules order and results point at them by index
+	if len(run.Tool.Driver.Rules) != len(policy.Rules) {
+		t.Fatalf("%d rules, want %d", len(run.Tool.Driver.Rules), len(policy.Rules))
+	}
+	for _, result := range run.Results {
+		if rule := run.Tool.Driver.Rules[result.RuleIndex]; rule.ID != result.RuleID {
+			t.Errorf("result %s has ruleIndex %d, which is rule %s", result.RuleID, result.RuleIndex, rule.ID)
+		}
+	}
+
+	wantLocations := []struct {
+		uri  string
+		line int
+	}{
+		{".diffloc.yaml", 1},     // max-additions budget
+		{".diffloc.yaml", 1},     // max-changed-files budget for scripts/
+		{"cmd/app/main.go", 101}, // first line past max-file-lines
+	}
+	if len(run.Results) != len(wantLocations) {
+		t.Fatalf("%d results, want %d", len(run.Results), len(wantLocations))
+	}
+	for i, want := range wantLocations {
+		loc := run.Results[i].Locations[0].PhysicalLocation
+		if loc.ArtifactLocation.URI != want.uri || loc.ArtifactLocation.URIBaseID != sarifRoot || loc.Region.StartLine != want.line {
+			t.Errorf("result %d location = %s (%s) line %d, want %s (%s) line %d", i,
+				loc.ArtifactLocation.URI, loc.ArtifactLocation.URIBaseID, loc.Region.StartLine, want.uri, sarifRoot, want.line)
+		}
+	}
+}
+
+func TestWriteSARIFWithoutConfigPath(t *testing.T) {
+	r := testReport()
+	r.Violations = append(r.Violations, Violation{Rule: policy.RuleMaxFileGrowth, Path: "docs/release notes.md", Limit: 50, Actual: 80},
+		Violation{Rule: "unknown-rule", Message: "ignored"})
+
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, r, SARIFOptions{}); err != nil {
+		t.Fatalf("WriteSARIF: %v", err)
+	}
+	results := decodeSARIF(t, buf.Bytes()).Runs[0].Results
+
+	// Unknown rules are dropped, and budgets have no location without a config path
+	if len(results) != 4 {
+		t.Fatalf("%d results, want 4", len(results))
+	}
+	for _, result := range results[:2] {
+		if len(result.Locations) != 0 {
+			t.Errorf("budget result %s has a location", result.RuleID)
+		}
+	}
+	if got := results[3].Locations[0].PhysicalLocation.ArtifactLocation.URI; got != "docs/release%20notes.md" {
+		t.Errorf("URI = %q, want docs/release%%20notes.md", got)
+	}
+}
+
+func decodeSARIF(t *testing.T, data []byte) sarifLog {
+	t.Helper()
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("invalid SARIF: %v", err)
+	}
+	if log.Version != SARIFVersion || len(log.Runs) != 1 {
+		t.Fatalf("SARIF version %q with %d runs, want %s with 1", log.Version, len(log.Runs), SARIFVersion)
+	}
+	return log
+}
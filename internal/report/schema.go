@@ -0,0 +1,8 @@
+package report
+
+import _ "embed"
+
+// JSONSchema is the published JSON Schema (draft 2020-12) for the current report version
+//
+//go:embed schema/report.v1.schema.json
+var JSONSchema []byte
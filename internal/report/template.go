@@ -0,0 +1,185 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nodelike/diffloc/internal/analyzer"
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+// TemplateData is the value passed to user templates. The embedded model.Stats
+// exposes the totals ({{.TotalLines}}, {{.NetChange}}, ...) and the changed and
+// unchanged file lists; Files holds every file ordered by path.
+type TemplateData struct {
+	*model.Stats
+	Files    []*model.FileInfo
+	Metadata Metadata
+}
+
+// NewTemplateData prepares analysis results for rendering with a user template
+func NewTemplateData(stats *model.Stats, meta Metadata) TemplateData {
+	files := make([]*model.FileInfo, 0, len(stats.ChangedFiles)+len(stats.UnchangedFiles))
+	files = append(files, stats.ChangedFiles...)
+	files = append(files, stats.UnchangedFiles...)
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	return TemplateData{Stats: stats, Files: files, Metadata: meta}
+}
+
+// templateColors maps color names accepted by the color helper to terminal colors
+var templateColors = map[string]lipgloss.Color{
+	"black":   lipgloss.Color("0"),
+	"red":     lipgloss.Color("#EF4444"),
+	"green":   lipgloss.Color("#10B981"),
+	"yellow":  lipgloss.Color("#F59E0B"),
+	"blue":    lipgloss.Color("#3B82F6"),
+	"magenta": lipgloss.Color("#EC4899"),
+	"cyan":    lipgloss.Color("#06B6D4"),
+	"white":   lipgloss.Color("#F9FAFB"),
+	"gray":    lipgloss.Color("#6B7280"),
+	"purple":  lipgloss.Color("#AF87FF"),
+}
+
+// TemplateFuncs are the helpers available to user templates
+var TemplateFuncs = template.FuncMap{
+	"padLeft":  padLeft,
+	"padRight": padRight,
+	"color":    colorize,
+	"bold": func(v any) string {
+		return lipgloss.NewStyle().Bold(true).Render(fmt.Sprint(v))
+	},
+	"num":      formatInt,
+	"signed":   formatSigned,
+	"humanize": humanize,
+	"percent":  percentOf,
+	"sortBy":   sortFiles,
+	"top":      topFiles,
+	"changed":  onlyChanged,
+	"lang":     analyzer.Language,
+	"add":      func(a, b int) int { return a + b },
+	"sub":      func(a, b int) int { return a - b },
+	"join":     strings.Join,
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+}
+
+// ParseTemplate parses a user template with the diffloc helpers installed
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(TemplateFuncs).Option("missingkey=error").Parse(text)
+}
+
+// WriteTemplate renders data with a parsed user template
+func WriteTemplate(w io.Writer, tmpl *template.Template, data TemplateData) error {
+	return tmpl.Execute(w, data)
+}
+
+// padLeft right-aligns v in a field of width characters
+func padLeft(width int, v any) string {
+	s := fmt.Sprint(v)
+	if n := utf8.RuneCountInString(s); n < width {
+		return strings.Repeat(" ", width-n) + s
+	}
+	return s
+}
+
+// padRight left-aligns v in a field of width characters
+func padRight(width int, v any) string {
+	s := fmt.Sprint(v)
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// colorize renders v in a named color or "#RRGGBB". Colors are dropped
+// automatically when stdout is not a terminal.
+func colorize(name string, v any) string {
+	color, ok := templateColors[strings.ToLower(name)]
+	if !ok {
+		color = lipgloss.Color(name)
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(fmt.Sprint(v))
+}
+
+// humanize abbreviates large numbers, e.g. 1234 becomes "1.2k" and 5600000 becomes "5.6M"
+func humanize(n int) string {
+	abs := math.Abs(float64(n))
+	switch {
+	case abs >= 1e9:
+		return trimZero(fmt.Sprintf("%.1f", float64(n)/1e9)) + "B"
+	case abs >= 1e6:
+		return trimZero(fmt.Sprintf("%.1f", float64(n)/1e6)) + "M"
+	case abs >= 1e3:
+		return trimZero(fmt.Sprintf("%.1f", float64(n)/1e3)) + "k"
+	default:
+		return fmt.Sprint(n)
+	}
+}
+
+func trimZero(s string) string {
+	return strings.TrimSuffix(s, ".0")
+}
+
+// percentOf formats part as a percentage of total with one decimal place
+func percentOf(part, total int) string {
+	if total == 0 {
+		return "0.0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(part)*100/float64(total))
+}
+
+// sortFiles returns a copy of files sorted by key: "path" ascending, or
+// "lines", "additions", "deletions", "changes" or "complexity" descending
+func sortFiles(key string, files []*model.FileInfo) ([]*model.FileInfo, error) {
+	var value func(f *model.FileInfo) int
+	switch key {
+	case "path", "name":
+	case "lines":
+		value = func(f *model.FileInfo) int { return f.Lines }
+	case "additions":
+		value = func(f *model.FileInfo) int { return f.Additions }
+	case "deletions":
+		value = func(f *model.FileInfo) int { return f.Deletions }
+	case "changes":
+		value = func(f *model.FileInfo) int { return f.Additions + f.Deletions }
+	case "complexity":
+		value = func(f *model.FileInfo) int { return f.Complexity }
+	default:
+		return nil, fmt.Errorf("sortBy: unknown key %q (expected path, lines, additions, deletions, changes or complexity)", key)
+	}
+
+	sorted := make([]*model.FileInfo, len(files))
+	copy(sorted, files)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if value == nil {
+			return sorted[i].Path < sorted[j].Path
+		}
+		return value(sorted[i]) > value(sorted[j])
+	})
+	return sorted, nil
+}
+
+// topFiles returns at most the first n files; a negative n yields none
+func topFiles(n int, files []*model.FileInfo) []*model.FileInfo {
+	return files[:min(max(n, 0), len(files))]
+}
+
+// onlyChanged returns the changed files
+func onlyChanged(files []*model.FileInfo) []*model.FileInfo {
+	changed := make([]*model.FileInfo, 0, len(files))
+	for _, f := range files {
+		if f.IsChanged {
+			changed = append(changed, f)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,66 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteTemplate(t *testing.T) {
+	text, err := os.ReadFile(filepath.Join("testdata", "summary.tmpl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := ParseTemplate("summary", string(text))
+	if err != nil {
+		t.Fatalf("ParseTemplate: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTemplate(&buf, tmpl, NewTemplateData(testStats(), testMetadata())); err != nil {
+		t.Fatalf("WriteTemplate: %v", err)
+	}
+	assertGolden(t, "summary.golden", buf.Bytes())
+}
+
+func TestWriteTemplateErrors(t *testing.T) {
+	tests := []string{
+		`{{.NoSuchField}}`,
+		`{{sortBy "size" .Files}}`,
+	}
+
+	for _, text := range tests {
+		tmpl, err := ParseTemplate("t", text)
+		if err != nil {
+			t.Fatalf("ParseTemplate(%q): %v", text, err)
+		}
+		if err := WriteTemplate(&bytes.Buffer{}, tmpl, NewTemplateData(testStats(), testMetadata())); err == nil {
+			t.Errorf("WriteTemplate(%q) succeeded, want an error", text)
+		}
+	}
+
+	if _, err := ParseTemplate("t", `{{noSuchFunc}}`); err == nil || !strings.Contains(err.Error(), "noSuchFunc") {
+		t.Errorf("ParseTemplate with an unknown function: err = %v", err)
+	}
+}
+
+func TestHumanize(t *testing.T) {
+	tests := map[int]string{0: "0", 999: "999", 1000: "1k", 1234: "1.2k", -1500: "-1.5k", 5600000: "5.6M", 2000000000: "2B"}
+	for n, want := range tests {
+		if got := humanize(n); got != want {
+			t.Errorf("humanize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestTopFiles(t *testing.T) {
+	files := NewTemplateData(testStats(), testMetadata()).Files
+
+	for n, want := range map[int]int{-1: 0, 0: 0, 2: 2, 4: 4, 10: 4} {
+		if got := len(topFiles(n, files)); got != want {
+			t.Errorf("top %d returned %d files, want %d", n, got, want)
+		}
+	}
+}
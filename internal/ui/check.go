@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+// PrintViolations prints the outcome of diffloc check: the change totals
+// followed by every budget or rule violation, or a success line if none
+func PrintViolations(stats *model.Stats, violations []*model.Violation) {
+	fmt.Print(renderViolations(stats, violations))
+}
+
+func renderViolations(stats *model.Stats, violations []*model.Violation) string {
+	var b strings.Builder
+
+	totals := fmt.Sprintf("%s %s across %d changed files",
+		additionStyle.Render(fmt.Sprintf("+%d", stats.TotalAdditions)),
+		deletionStyle.Render(fmt.Sprintf("-%d", stats.TotalDeletions)),
+		stats.ChangedCount)
+
+	if len(violations) == 0 {
+		b.WriteString(summaryPositiveStyle.Render("✓ Within budget"))
+		b.WriteString("  " + totals + "\n")
+		return b.String()
+	}
+
+	label := "violations"
+	if len(violations) == 1 {
+		label = "violation"
+	}
+	b.WriteString(summaryNegativeStyle.Render(fmt.Sprintf("✗ %d %s", len(violations), label)))
+	b.WriteString("  " + totals + "\n\n")
+	b.WriteString(renderViolationList(violations))
+
+	return b.String()
+}
+
+// renderViolationList renders one line per violation: the rule ID and its message
+func renderViolationList(violations []*model.Violation) string {
+	var b strings.Builder
+
+	ruleWidth := 0
+	for _, v := range violations {
+		ruleWidth = max(ruleWidth, lipgloss.Width(v.Rule))
+	}
+
+	ruleStyle := lipgloss.NewStyle().Foreground(warningColor).Bold(true).Width(ruleWidth)
+	for _, v := range violations {
+		fmt.Fprintf(&b, "    %s  %s\n", ruleStyle.Render(v.Rule), filePathStyle.Render(v.Message))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,350 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nodelike/diffloc/internal/model"
+)
+
+// Sections of the comparison view, cycled with v
+const (
+	compareFiles = iota
+	compareDirectories
+	compareLanguages
+	compareSectionCount
+)
+
+// CompareModel is the TUI state of diffloc compare
+type CompareModel struct {
+	comparison *model.Comparison
+	oldLabel   string
+	newLabel   string
+	section    int
+	byName     bool // Sort by name instead of by the size of the line change
+	viewport   viewport.Model
+	ready      bool
+}
+
+// NewCompareModel creates a comparison view; the labels name the old and new report
+func NewCompareModel(c *model.Comparison, oldLabel, newLabel string) CompareModel {
+	return CompareModel{
+		comparison: c,
+		oldLabel:   oldLabel,
+		newLabel:   newLabel,
+	}
+}
+
+func (m CompareModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages and updates the model
+func (m CompareModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		footerHeight := 6
+		if !m.ready {
+			m.viewport = viewport.New(msg.Width, msg.Height-footerHeight)
+			m.viewport.SetContent(m.renderContent(false))
+			m.viewport.GotoBottom()
+			m.ready = true
+		} else {
+			m.viewport.Width = msg.Width
+			m.viewport.Height = msg.Height - footerHeight
+		}
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return m, tea.Quit
+		case "v":
+			m.section = (m.section + 1) % compareSectionCount
+			m.viewport.SetContent(m.renderContent(false))
+			m.viewport.GotoBottom()
+			return m, nil
+		case "n", "c":
+			m.byName = msg.String() == "n"
+			m.sortDeltas()
+			m.viewport.SetContent(m.renderContent(false))
+			m.viewport.GotoBottom()
+			return m, nil
+		}
+	}
+
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// View renders the TUI
+func (m CompareModel) View() string {
+	if !m.ready {
+		return "\nInitializing..."
+	}
+	return fmt.Sprintf("%s\n%s\n", m.viewport.View(), m.renderFooter())
+}
+
+// renderContent renders the selected section followed by the summary, or every
+// section when all is set
+func (m CompareModel) renderContent(all bool) string {
+	var b strings.Builder
+
+	b.WriteString("\n")
+	b.WriteString(headerStyle.Render(fmt.Sprintf("✨ diffloc — %s → %s", m.oldLabel, m.newLabel)))
+
+	for section := 0; section < compareSectionCount; section++ {
+		if !all && section != m.section {
+			continue
+		}
+
+		switch section {
+		case compareFiles:
+			b.WriteString(compareSectionHeader(len(m.comparison.Files), "Added, Deleted and Resized Files"))
+			b.WriteString(renderFileDeltaTable(m.comparison.Files))
+		case compareDirectories:
+			b.WriteString(compareSectionHeader(len(m.comparison.Directories), "Directories"))
+			b.WriteString(renderGroupDeltaTable(m.comparison.Directories))
+		case compareLanguages:
+			b.WriteString(compareSectionHeader(len(m.comparison.Languages), "Languages"))
+			b.WriteString(renderGroupDeltaTable(m.comparison.Languages))
+		}
+	}
+
+	b.WriteString(m.renderSummary())
+	return b.String()
+}
+
+func compareSectionHeader(count int, title string) string {
+	return sectionHeaderStyle.Render(badgeStyle.Render(fmt.Sprintf("%d", count))+" "+title) + "\n"
+}
+
+// renderFileDeltaTable renders the old and new line count of each file
+func renderFileDeltaTable(files []*model.FileDelta) string {
+	if len(files) == 0 {
+		return mutedNumberStyle.Render("    (none)") + "\n"
+	}
+
+	var b strings.Builder
+
+	b.WriteString("    ")
+	for _, column := range []string{"OLD", "NEW", "CHANGE"} {
+		b.WriteString(tableHeaderStyle.Render(fmt.Sprintf("%-10s", column)))
+		b.WriteString("  ")
+	}
+	b.WriteString(tableHeaderStyle.Render("FILE PATH"))
+	b.WriteString("\n")
+
+	b.WriteString("    ")
+	b.WriteString(separatorStyle.Render(strings.Repeat("─", 90)))
+	b.WriteString("\n")
+
+	for _, file := range files {
+		b.WriteString("    ")
+		b.WriteString(renderDeltaCells(file.Lines))
+
+		switch file.Status {
+		case model.StatusAdded:
+			b.WriteString(filePathStyle.Render("+ " + file.Path))
+		case model.StatusDeleted:
+			b.WriteString(unchangedFilePathStyle.Render("- " + file.Path))
+		default:
+			b.WriteString(filePathStyle.Render("◆ " + file.Path))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// renderGroupDeltaTable renders the old and new file and line counts of each bucket
+func renderGroupDeltaTable(groups []*model.GroupDelta) string {
+	if len(groups) == 0 {
+		return mutedNumberStyle.Render("    (none)") + "\n"
+	}
+
+	var b strings.Builder
+
+	b.WriteString("    ")
+	for _, column := range []string{"OLD", "NEW", "CHANGE", "FILES"} {
+		b.WriteString(tableHeaderStyle.Render(fmt.Sprintf("%-10s", column)))
+		b.WriteString("  ")
+	}
+	b.WriteString(tableHeaderStyle.Render("GROUP"))
+	b.WriteString("\n")
+
+	b.WriteString("    ")
+	b.WriteString(separatorStyle.Render(strings.Repeat("─", 90)))
+	b.WriteString("\n")
+
+	for _, group := range groups {
+		b.WriteString("    ")
+		b.WriteString(renderDeltaCells(group.Lines))
+		b.WriteString(summaryValueStyle.Render(fmt.Sprintf("%-10s", fmt.Sprintf("%d→%d", group.Files.Old, group.Files.New))))
+		b.WriteString("  ")
+		b.WriteString(filePathStyle.Render(group.Name))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// renderDeltaCells renders the OLD, NEW and CHANGE columns of a line count
+func renderDeltaCells(d model.Delta) string {
+	var b strings.Builder
+
+	for _, count := range []int{d.Old, d.New} {
+		if count > 0 {
+			b.WriteString(summaryValueStyle.Render(fmt.Sprintf("%-10d", count)))
+		} else {
+			b.WriteString(mutedNumberStyle.Render(fmt.Sprintf("%-10s", "—")))
+		}
+		b.WriteString("  ")
+	}
+
+	change := d.Change()
+	switch {
+	case change > 0:
+		b.WriteString(additionStyle.Render(fmt.Sprintf("+%-9d", change)))
+	case change < 0:
+		b.WriteString(deletionStyle.Render(fmt.Sprintf("%-10d", change)))
+	default:
+		b.WriteString(mutedNumberStyle.Render(fmt.Sprintf("%-10s", "—")))
+	}
+	b.WriteString("  ")
+
+	return b.String()
+}
+
+// renderSummary renders the summary box with the overall growth
+func (m CompareModel) renderSummary() string {
+	c := m.comparison
+	var content strings.Builder
+
+	content.WriteString(tableHeaderStyle.Render("📊 SUMMARY"))
+	content.WriteString("\n")
+	content.WriteString(separatorStyle.Render(strings.Repeat("─", 60)))
+	content.WriteString("\n")
+
+	content.WriteString(summaryLabelStyle.Render("Lines:"))
+	content.WriteString("  ")
+	content.WriteString(summaryValueStyle.Render(fmt.Sprintf("%d → %d", c.Total.Lines.Old, c.Total.Lines.New)))
+	content.WriteString("  ")
+	content.WriteString(renderGrowth(c.Total.Lines))
+	content.WriteString("\n")
+
+	content.WriteString(summaryLabelStyle.Render("Files:"))
+	content.WriteString("  ")
+	content.WriteString(summaryValueStyle.Render(fmt.Sprintf("%d → %d", c.Total.Files.Old, c.Total.Files.New)))
+	content.WriteString("  ")
+	content.WriteString(additionStyle.Render(fmt.Sprintf("+%d", c.AddedFiles)))
+	content.WriteString(summaryLabelStyle.Render(" added  •  "))
+	content.WriteString(deletionStyle.Render(fmt.Sprintf("-%d", c.DeletedFiles)))
+	content.WriteString(summaryLabelStyle.Render(" deleted  •  "))
+	content.WriteString(lipgloss.NewStyle().Foreground(accentColor).Bold(true).Render(fmt.Sprintf("%d", c.ModifiedFiles)))
+	content.WriteString(summaryLabelStyle.Render(" resized  •  "))
+	content.WriteString(mutedNumberStyle.Render(fmt.Sprintf("%d", c.UnchangedFiles)))
+	content.WriteString(summaryLabelStyle.Render(" unchanged"))
+
+	return summaryBoxStyle.Render(content.String())
+}
+
+// renderGrowth renders a line count change with its percentage of the old count
+func renderGrowth(d model.Delta) string {
+	change := d.Change()
+
+	percent := ""
+	if d.Old > 0 {
+		percent = fmt.Sprintf(" (%+.1f%%)", float64(change)*100/float64(d.Old))
+	}
+
+	switch {
+	case change > 0:
+		return summaryPositiveStyle.Render(fmt.Sprintf("▲ +%d%s", change, percent))
+	case change < 0:
+		return summaryNegativeStyle.Render(fmt.Sprintf("▼ %d%s", change, percent))
+	default:
+		return summaryNeutralStyle.Render("● no change")
+	}
+}
+
+// renderFooter renders the footer with keybindings
+func (m CompareModel) renderFooter() string {
+	var footer strings.Builder
+
+	keybindings := []string{
+		keybindingKeyStyle.Render("↑↓/j/k") + " " + keybindingDescStyle.Render("scroll"),
+		keybindingKeyStyle.Render("v") + " " + keybindingDescStyle.Render("files/directories/languages"),
+		keybindingKeyStyle.Render("n") + " " + keybindingDescStyle.Render("name"),
+		keybindingKeyStyle.Render("c") + " " + keybindingDescStyle.Render("change"),
+		keybindingKeyStyle.Render("q") + " " + keybindingDescStyle.Render("quit"),
+	}
+	footer.WriteString(strings.Join(keybindings, separatorStyle.Render("  •  ")))
+
+	sortMode := "change ↓ desc"
+	if m.byName {
+		sortMode = "name ⇅ A→Z"
+	}
+	footer.WriteString("\n\n")
+	footer.WriteString(mutedNumberStyle.Render("Sort: "))
+	footer.WriteString(lipgloss.NewStyle().Foreground(accentColor).Bold(true).Render(sortMode))
+
+	return footerStyle.Render(footer.String())
+}
+
+// sortDeltas orders every table by name or by the size of the line change
+func (m *CompareModel) sortDeltas() {
+	byChange := func(ci, cj int, ni, nj string) bool {
+		if m.byName || ci == cj {
+			return ni < nj
+		}
+		return ci > cj
+	}
+
+	files := m.comparison.Files
+	sort.Slice(files, func(i, j int) bool {
+		return byChange(absInt(files[i].Lines.Change()), absInt(files[j].Lines.Change()), files[i].Path, files[j].Path)
+	})
+	for _, groups := range [][]*model.GroupDelta{m.comparison.Directories, m.comparison.Languages} {
+		sort.Slice(groups, func(i, j int) bool {
+			return byChange(absInt(groups[i].Lines.Change()), absInt(groups[j].Lines.Change()), groups[i].Name, groups[j].Name)
+		})
+	}
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// RunCompare starts the comparison TUI and prints every section once it exits
+func RunCompare(c *model.Comparison, oldLabel, newLabel string) error {
+	p := tea.NewProgram(
+		NewCompareModel(c, oldLabel, newLabel),
+		tea.WithAltScreen(),
+		tea.WithMouseCellMotion(),
+	)
+
+	finalModel, err := p.Run()
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(finalModel.(CompareModel).renderContent(true))
+	fmt.Print("\n")
+	return nil
+}
+
+// PrintCompare prints every section of a comparison without interactivity
+func PrintCompare(c *model.Comparison, oldLabel, newLabel string) error {
+	fmt.Print(NewCompareModel(c, oldLabel, newLabel).renderContent(true))
+	fmt.Print("\n")
+	return nil
+}
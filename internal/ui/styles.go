@@ -59,6 +59,10 @@ var (
 			Foreground(textColor).
 			Bold(false)
 
+	violationPathStyle = lipgloss.NewStyle().
+				Foreground(warningColor).
+				Bold(true)
+
 	unchangedFilePathStyle = lipgloss.NewStyle().
 				Foreground(mutedColor).
 				Italic(true)
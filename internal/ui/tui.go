@@ -2,12 +2,14 @@ package ui
 
 import (
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nodelike/diffloc/internal/analyzer"
 	"github.com/nodelike/diffloc/internal/model"
 )
 
@@ -19,6 +21,10 @@ type Model struct {
 	err         error
 	viewport    viewport.Model
 	ready       bool
+	showDetails bool // Expand changed files into the Go declarations they touch and their uncovered lines
+	showGroups  bool // Render grouped rollups instead of flat file lists
+
+	violations map[string][]*model.Violation // Policy violations by file path
 }
 
 // NewModel creates a new TUI model
@@ -28,7 +34,21 @@ func NewModel(stats *model.Stats) Model {
 		sortMode:    model.SortByLines,
 		sortReverse: false,
 		ready:       false,
+		showDetails: hasDetails(stats),
+		showGroups:  len(stats.Groups) > 0,
+		violations:  violationsByPath(stats.Violations),
+	}
+}
+
+// violationsByPath indexes per-file violations by path
+func violationsByPath(violations []*model.Violation) map[string][]*model.Violation {
+	byPath := make(map[string][]*model.Violation)
+	for _, v := range violations {
+		if v.Path != "" {
+			byPath[v.Path] = append(byPath[v.Path], v)
+		}
 	}
+	return byPath
 }
 
 func (m Model) Init() tea.Cmd {
@@ -102,6 +122,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.viewport.SetContent(m.renderFullContent())
 			m.viewport.GotoBottom()
 			return m, nil
+		case "v":
+			if len(m.stats.Groups) == 0 {
+				break
+			}
+			m.showGroups = !m.showGroups
+			m.viewport.SetContent(m.renderFullContent())
+			m.viewport.GotoBottom()
+			return m, nil
+		case "s":
+			if !hasDetails(m.stats) {
+				break
+			}
+			m.showDetails = !m.showDetails
+			m.viewport.SetContent(m.renderFullContent())
+			return m, nil
 		}
 	}
 
@@ -132,7 +167,12 @@ func (m Model) renderFullContent() string {
 	b.WriteString(headerStyle.Render("✨ diffloc — Diff Line Counter"))
 	isGitRepo := m.stats.TotalAdditions > 0 || m.stats.TotalDeletions > 0 || m.stats.ChangedCount > 0
 
-	if isGitRepo {
+	if m.showGroups {
+		groupsBadge := badgeStyle.Render(fmt.Sprintf("%d", len(m.stats.Groups)))
+		b.WriteString(sectionHeaderStyle.Render(groupsBadge + " Groups by " + m.stats.GroupBy))
+		b.WriteString("\n")
+		b.WriteString(m.renderGroupTable(m.stats.Groups, isGitRepo))
+	} else if isGitRepo {
 		if len(m.stats.UnchangedFiles) > 0 {
 			unchangedBadge := badgeStyle.Render(fmt.Sprintf("%d", len(m.stats.UnchangedFiles)))
 			b.WriteString(sectionHeaderStyle.Render(unchangedBadge + " Unchanged Files"))
@@ -156,6 +196,12 @@ func (m Model) renderFullContent() string {
 		}
 	}
 
+	if isGitRepo {
+		b.WriteString(m.renderOwnersPanel())
+	}
+
+	b.WriteString(m.renderViolationsPanel())
+
 	b.WriteString(m.renderSummary(isGitRepo))
 
 	return b.String()
@@ -180,6 +226,12 @@ func (m Model) renderFileTable(files []*model.FileInfo, isChanged bool, showGitC
 		b.WriteString("  ")
 	}
 
+	showComplexity := hasComplexity(m.stats)
+	if showComplexity {
+		b.WriteString(tableHeaderStyle.Render(fmt.Sprintf("%-12s", "COMPLEXITY")))
+		b.WriteString("  ")
+	}
+
 	b.WriteString(tableHeaderStyle.Render("FILE PATH"))
 	b.WriteString("\n")
 
@@ -188,6 +240,9 @@ func (m Model) renderFileTable(files []*model.FileInfo, isChanged bool, showGitC
 	if !showGitColumns {
 		sepLength = 60
 	}
+	if showComplexity {
+		sepLength += 14
+	}
 	b.WriteString(separatorStyle.Render(strings.Repeat("─", sepLength)))
 	b.WriteString("\n")
 
@@ -216,6 +271,11 @@ func (m Model) renderFileTable(files []*model.FileInfo, isChanged bool, showGitC
 			b.WriteString("  ")
 		}
 
+		if showComplexity {
+			b.WriteString(renderComplexityCell(file))
+			b.WriteString("  ")
+		}
+
 		pathPrefix := ""
 		if isChanged && showGitColumns {
 			if file.Additions > 0 && file.Deletions > 0 {
@@ -226,13 +286,412 @@ func (m Model) renderFileTable(files []*model.FileInfo, isChanged bool, showGitC
 				pathPrefix = "- "
 			}
 		}
-		b.WriteString(filePathStyle.Render(pathPrefix + file.Path))
+		if len(m.violations[file.Path]) > 0 {
+			b.WriteString(violationPathStyle.Render("⚠ " + pathPrefix + file.Path))
+		} else {
+			b.WriteString(filePathStyle.Render(pathPrefix + file.Path))
+		}
+		b.WriteString("\n")
+
+		if m.showDetails && isChanged {
+			showCoverage := file.Coverage != nil && file.Coverage.Executable > 0
+			if len(file.Symbols) > 0 {
+				b.WriteString(m.renderSymbols(file.Symbols, showCoverage))
+			}
+			if showCoverage {
+				b.WriteString(m.renderFileCoverage(file.Coverage))
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// renderGroupTable renders aggregated rollups with their share of the total
+func (m Model) renderGroupTable(groups []*model.Group, showGitColumns bool) string {
+	if len(groups) == 0 {
+		return mutedNumberStyle.Render("    (none)") + "\n"
+	}
+
+	var b strings.Builder
+
+	b.WriteString("    ")
+	b.WriteString(tableHeaderStyle.Render(fmt.Sprintf("%-10s", "LINES")))
+	b.WriteString("  ")
+	if showGitColumns {
+		b.WriteString(tableHeaderStyle.Render(fmt.Sprintf("%-10s", "ADDED")))
+		b.WriteString("  ")
+		b.WriteString(tableHeaderStyle.Render(fmt.Sprintf("%-10s", "REMOVED")))
+		b.WriteString("  ")
+	}
+	b.WriteString(tableHeaderStyle.Render(fmt.Sprintf("%-10s", "FILES")))
+	b.WriteString("  ")
+	b.WriteString(tableHeaderStyle.Render(fmt.Sprintf("%-10s", "SHARE")))
+	b.WriteString("  ")
+	if showGitColumns {
+		b.WriteString(tableHeaderStyle.Render(fmt.Sprintf("%-10s", "CHURN")))
+		b.WriteString("  ")
+	}
+	b.WriteString(tableHeaderStyle.Render("GROUP"))
+	b.WriteString("\n")
+
+	b.WriteString("    ")
+	sepLength := 90
+	if showGitColumns {
+		sepLength = 114
+	}
+	b.WriteString(separatorStyle.Render(strings.Repeat("─", sepLength)))
+	b.WriteString("\n")
+
+	for _, group := range groups {
+		b.WriteString("    ")
+		b.WriteString(summaryValueStyle.Render(fmt.Sprintf("%-10d", group.Lines)))
+		b.WriteString("  ")
+
+		if showGitColumns {
+			if group.Additions > 0 {
+				b.WriteString(additionStyle.Render(fmt.Sprintf("+%-9d", group.Additions)))
+			} else {
+				b.WriteString(mutedNumberStyle.Render(fmt.Sprintf("%-10s", "—")))
+			}
+			b.WriteString("  ")
+
+			if group.Deletions > 0 {
+				b.WriteString(deletionStyle.Render(fmt.Sprintf("-%-9d", group.Deletions)))
+			} else {
+				b.WriteString(mutedNumberStyle.Render(fmt.Sprintf("%-10s", "—")))
+			}
+			b.WriteString("  ")
+		}
+
+		files := fmt.Sprintf("%d", group.Files)
+		if showGitColumns && group.ChangedFiles > 0 {
+			files = fmt.Sprintf("%d (%d)", group.Files, group.ChangedFiles)
+		}
+		b.WriteString(summaryValueStyle.Render(fmt.Sprintf("%-10s", files)))
+		b.WriteString("  ")
+
+		b.WriteString(mutedNumberStyle.Render(fmt.Sprintf("%-10s", fmt.Sprintf("%.1f%%", group.LineShare))))
+		b.WriteString("  ")
+
+		if showGitColumns {
+			if group.ChangeShare > 0 {
+				accentStyle := lipgloss.NewStyle().Foreground(accentColor).Bold(true)
+				b.WriteString(accentStyle.Render(fmt.Sprintf("%-10s", fmt.Sprintf("%.1f%%", group.ChangeShare))))
+			} else {
+				b.WriteString(mutedNumberStyle.Render(fmt.Sprintf("%-10s", "—")))
+			}
+			b.WriteString("  ")
+		}
+
+		b.WriteString(filePathStyle.Render(group.Name))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// ownerReview aggregates the changed lines a single owner needs to review
+type ownerReview struct {
+	owner     string
+	files     int
+	additions int
+	deletions int
+}
+
+// reviewLoad returns the CODEOWNERS owners touched by the changed files, busiest
+// first. Returns nil if no changed file has an owner.
+func (m Model) reviewLoad() []*ownerReview {
+	byOwner := make(map[string]*ownerReview)
+	hasOwners := false
+
+	for _, file := range m.stats.ChangedFiles {
+		owners := file.Owners
+		if len(owners) > 0 {
+			hasOwners = true
+		} else {
+			owners = []string{analyzer.NoOwner}
+		}
+
+		for _, owner := range owners {
+			review, ok := byOwner[owner]
+			if !ok {
+				review = &ownerReview{owner: owner}
+				byOwner[owner] = review
+			}
+			review.files++
+			review.additions += file.Additions
+			review.deletions += file.Deletions
+		}
+	}
+
+	if !hasOwners {
+		return nil
+	}
+
+	reviews := make([]*ownerReview, 0, len(byOwner))
+	for _, review := range byOwner {
+		reviews = append(reviews, review)
+	}
+	sort.Slice(reviews, func(i, j int) bool {
+		li := reviews[i].additions + reviews[i].deletions
+		lj := reviews[j].additions + reviews[j].deletions
+		if li != lj {
+			return li > lj
+		}
+		return reviews[i].owner < reviews[j].owner
+	})
+	return reviews
+}
+
+// renderOwnersPanel renders which CODEOWNERS teams a change touches and how many
+// lines each of them needs to review
+func (m Model) renderOwnersPanel() string {
+	reviews := m.reviewLoad()
+	if len(reviews) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+
+	ownersBadge := badgeStyle.Render(fmt.Sprintf("%d", len(reviews)))
+	b.WriteString(sectionHeaderStyle.Render(ownersBadge + " Owners"))
+	b.WriteString("\n")
+
+	b.WriteString("    ")
+	b.WriteString(tableHeaderStyle.Render(fmt.Sprintf("%-10s", "REVIEW")))
+	b.WriteString("  ")
+	b.WriteString(tableHeaderStyle.Render(fmt.Sprintf("%-10s", "ADDED")))
+	b.WriteString("  ")
+	b.WriteString(tableHeaderStyle.Render(fmt.Sprintf("%-10s", "REMOVED")))
+	b.WriteString("  ")
+	b.WriteString(tableHeaderStyle.Render(fmt.Sprintf("%-10s", "FILES")))
+	b.WriteString("  ")
+	b.WriteString(tableHeaderStyle.Render("OWNER"))
+	b.WriteString("\n")
+
+	b.WriteString("    ")
+	b.WriteString(separatorStyle.Render(strings.Repeat("─", 90)))
+	b.WriteString("\n")
+
+	accentStyle := lipgloss.NewStyle().Foreground(accentColor).Bold(true)
+	for _, review := range reviews {
+		b.WriteString("    ")
+		b.WriteString(summaryValueStyle.Render(fmt.Sprintf("%-10d", review.additions+review.deletions)))
+		b.WriteString("  ")
+		if review.additions > 0 {
+			b.WriteString(additionStyle.Render(fmt.Sprintf("+%-9d", review.additions)))
+		} else {
+			b.WriteString(mutedNumberStyle.Render(fmt.Sprintf("%-10s", "—")))
+		}
+		b.WriteString("  ")
+		if review.deletions > 0 {
+			b.WriteString(deletionStyle.Render(fmt.Sprintf("-%-9d", review.deletions)))
+		} else {
+			b.WriteString(mutedNumberStyle.Render(fmt.Sprintf("%-10s", "—")))
+		}
+		b.WriteString("  ")
+		b.WriteString(mutedNumberStyle.Render(fmt.Sprintf("%-10d", review.files)))
+		b.WriteString("  ")
+		b.WriteString(accentStyle.Render(review.owner))
 		b.WriteString("\n")
 	}
 
 	return b.String()
 }
 
+// renderViolationsPanel lists the policy rules broken by the analyzed files
+func (m Model) renderViolationsPanel() string {
+	if len(m.stats.Violations) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+
+	violationsBadge := badgeStyle.Render(fmt.Sprintf("%d", len(m.stats.Violations)))
+	b.WriteString(sectionHeaderStyle.Render(violationsBadge + " Policy Violations"))
+	b.WriteString("\n")
+	b.WriteString(renderViolationList(m.stats.Violations))
+
+	return b.String()
+}
+
+// renderComplexityCell renders a file's complexity with its delta against HEAD
+func renderComplexityCell(file *model.FileInfo) string {
+	value := fmt.Sprintf("%d", file.Complexity)
+	cell := summaryValueStyle.Render(value)
+	width := len(value)
+
+	if file.ComplexityDelta != 0 {
+		delta := fmt.Sprintf(" (%+d)", file.ComplexityDelta)
+		style := deletionStyle
+		if file.ComplexityDelta < 0 {
+			style = additionStyle
+		}
+		cell += style.Render(delta)
+		width += len(delta)
+	}
+
+	if width < 12 {
+		cell += strings.Repeat(" ", 12-width)
+	}
+	return cell
+}
+
+// hasComplexity reports whether complexity metrics were computed for any file
+func hasComplexity(stats *model.Stats) bool {
+	return stats.TotalComplexity != 0 || stats.ComplexityDelta != 0
+}
+
+// renderSymbols renders the Go declarations touched by a file as a tree below
+// its row; more keeps the tree open for the coverage line that follows
+func (m Model) renderSymbols(symbols []*model.SymbolChange, more bool) string {
+	var b strings.Builder
+
+	indent := m.detailIndent()
+	for i, sym := range symbols {
+		branch := "├─ "
+		if i == len(symbols)-1 && !more {
+			branch = "└─ "
+		}
+
+		var marker string
+		var markerStyle lipgloss.Style
+		switch sym.Change {
+		case model.SymbolAdded:
+			marker, markerStyle = "+", additionStyle
+		case model.SymbolRemoved:
+			marker, markerStyle = "-", deletionStyle
+		default:
+			marker, markerStyle = "~", summaryNeutralStyle
+		}
+
+		b.WriteString(indent)
+		b.WriteString(separatorStyle.Render(branch))
+		b.WriteString(markerStyle.Render(marker))
+		b.WriteString(" ")
+		b.WriteString(mutedNumberStyle.Render(fmt.Sprintf("%-6s ", sym.Kind)))
+		b.WriteString(filePathStyle.Render(sym.Name))
+		if sym.Additions > 0 {
+			b.WriteString(additionStyle.Render(fmt.Sprintf("  +%d", sym.Additions)))
+		}
+		if sym.Deletions > 0 {
+			b.WriteString(deletionStyle.Render(fmt.Sprintf("  -%d", sym.Deletions)))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// renderFileCoverage renders the coverage of a file's added lines as the last branch below its row
+func (m Model) renderFileCoverage(coverage *model.Coverage) string {
+	var b strings.Builder
+
+	b.WriteString(m.detailIndent())
+	b.WriteString(separatorStyle.Render("└─ "))
+	b.WriteString(coverageStyle(coverage).Render(fmt.Sprintf("%.1f%%", coverage.Percent())))
+	b.WriteString(mutedNumberStyle.Render(fmt.Sprintf(" of %d changed lines covered", coverage.Executable)))
+	if len(coverage.Uncovered) > 0 {
+		b.WriteString(mutedNumberStyle.Render("  •  uncovered "))
+		b.WriteString(deletionStyle.Render(lineRanges(coverage.Uncovered, 8)))
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// detailIndent aligns drill-down trees with the file path column
+func (m Model) detailIndent() string {
+	indentWidth := 40
+	if hasComplexity(m.stats) {
+		indentWidth += 14
+	}
+	return strings.Repeat(" ", indentWidth)
+}
+
+// coverageStyle colors a coverage percentage: 80% and up is good
+func coverageStyle(coverage *model.Coverage) lipgloss.Style {
+	if coverage.Percent() >= 80 {
+		return summaryPositiveStyle
+	}
+	return summaryNegativeStyle
+}
+
+// lineRanges compacts sorted line numbers into ranges such as "3-5, 9",
+// listing at most limit ranges
+func lineRanges(lines []int, limit int) string {
+	var ranges []string
+	for i := 0; i < len(lines); {
+		j := i
+		for j+1 < len(lines) && lines[j+1] == lines[j]+1 {
+			j++
+		}
+		if len(ranges) == limit {
+			ranges = append(ranges, "…")
+			break
+		}
+		if i == j {
+			ranges = append(ranges, fmt.Sprintf("%d", lines[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", lines[i], lines[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(ranges, ", ")
+}
+
+// hasDetails reports whether changed files have a drill-down to expand
+func hasDetails(stats *model.Stats) bool {
+	return hasSymbols(stats) || stats.Coverage != nil
+}
+
+// hasSymbols reports whether any changed file carries Go symbol changes
+func hasSymbols(stats *model.Stats) bool {
+	for _, file := range stats.ChangedFiles {
+		if len(file.Symbols) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// renderSymbolSummary summarizes touched Go declarations, e.g. "12 functions in 3 packages"
+func (m Model) renderSymbolSummary() string {
+	var funcs, types, values int
+	packages := make(map[string]bool)
+
+	for _, file := range m.stats.ChangedFiles {
+		for _, sym := range file.Symbols {
+			switch sym.Kind {
+			case model.SymbolFunc, model.SymbolMethod:
+				funcs++
+			case model.SymbolType:
+				types++
+			default:
+				values++
+			}
+		}
+		if len(file.Symbols) > 0 {
+			packages[filepath.Dir(file.Path)] = true
+		}
+	}
+
+	accentStyle := lipgloss.NewStyle().Foreground(accentColor).Bold(true)
+
+	var b strings.Builder
+	b.WriteString(accentStyle.Render(fmt.Sprintf("%d", funcs)))
+	b.WriteString(summaryLabelStyle.Render(" functions  •  "))
+	b.WriteString(accentStyle.Render(fmt.Sprintf("%d", types)))
+	b.WriteString(summaryLabelStyle.Render(" types  •  "))
+	b.WriteString(accentStyle.Render(fmt.Sprintf("%d", values)))
+	b.WriteString(summaryLabelStyle.Render(" vars/consts  in "))
+	b.WriteString(summaryValueStyle.Render(fmt.Sprintf("%d", len(packages))))
+	b.WriteString(summaryLabelStyle.Render(" packages"))
+
+	return b.String()
+}
+
 // renderSummary renders the summary box
 func (m Model) renderSummary(isGitRepo bool) string {
 	var content strings.Builder
@@ -287,6 +746,50 @@ func (m Model) renderSummary(isGitRepo bool) string {
 		content.WriteString(summaryLabelStyle.Render(" added  •  "))
 		content.WriteString(deletionStyle.Render(fmt.Sprintf("-%d", m.stats.TotalDeletions)))
 		content.WriteString(summaryLabelStyle.Render(" removed"))
+
+		if m.stats.TotalAdditions > 0 {
+			content.WriteString("\n")
+			content.WriteString(summaryLabelStyle.Render("Tests:"))
+			content.WriteString("      ")
+			content.WriteString(additionStyle.Render(fmt.Sprintf("+%d", m.stats.TestAdditions)))
+			content.WriteString(summaryLabelStyle.Render(" test  •  "))
+			content.WriteString(additionStyle.Render(fmt.Sprintf("+%d", m.stats.ProdAdditions)))
+			content.WriteString(summaryLabelStyle.Render(" production"))
+			if m.stats.ProdAdditions > 0 {
+				content.WriteString(summaryLabelStyle.Render("  •  "))
+				content.WriteString(summaryValueStyle.Render(fmt.Sprintf("%d", m.stats.TestAdditions*100/m.stats.ProdAdditions)))
+				content.WriteString(summaryLabelStyle.Render(" test lines per 100"))
+			}
+		}
+
+		if coverage := m.stats.Coverage; coverage != nil {
+			content.WriteString("\n")
+			content.WriteString(summaryLabelStyle.Render("Coverage:"))
+			content.WriteString("    ")
+			content.WriteString(coverageStyle(coverage).Render(fmt.Sprintf("%.1f%%", coverage.Percent())))
+			content.WriteString(summaryLabelStyle.Render(" of "))
+			content.WriteString(summaryValueStyle.Render(fmt.Sprintf("%d", coverage.Executable)))
+			content.WriteString(summaryLabelStyle.Render(" changed executable lines  •  "))
+			content.WriteString(deletionStyle.Render(fmt.Sprintf("%d", coverage.Executable-coverage.Covered)))
+			content.WriteString(summaryLabelStyle.Render(" uncovered"))
+		}
+
+		if hasComplexity(m.stats) {
+			content.WriteString("\n")
+			content.WriteString(summaryLabelStyle.Render("Complexity:"))
+			content.WriteString("  ")
+			content.WriteString(summaryValueStyle.Render(fmt.Sprintf("%d", m.stats.TotalComplexity)))
+			content.WriteString(summaryLabelStyle.Render(" total  •  "))
+			content.WriteString(renderComplexityDelta(m.stats.ComplexityDelta))
+			content.WriteString(summaryLabelStyle.Render(" in changed files"))
+		}
+
+		if hasSymbols(m.stats) {
+			content.WriteString("\n")
+			content.WriteString(summaryLabelStyle.Render("Go Symbols:"))
+			content.WriteString(" ")
+			content.WriteString(m.renderSymbolSummary())
+		}
 	} else {
 		content.WriteString(summaryLabelStyle.Render("Total Files:"))
 		content.WriteString(" ")
@@ -295,11 +798,40 @@ func (m Model) renderSummary(isGitRepo bool) string {
 		content.WriteString(summaryLabelStyle.Render("Total Lines:"))
 		content.WriteString(" ")
 		content.WriteString(summaryValueStyle.Render(fmt.Sprintf("%d", m.stats.TotalLines)))
+
+		if m.stats.TestLines > 0 {
+			content.WriteString("\n")
+			content.WriteString(summaryLabelStyle.Render("Tests:"))
+			content.WriteString("       ")
+			content.WriteString(summaryValueStyle.Render(fmt.Sprintf("%d", m.stats.TestLines)))
+			content.WriteString(summaryLabelStyle.Render(" test  •  "))
+			content.WriteString(summaryValueStyle.Render(fmt.Sprintf("%d", m.stats.ProdLines)))
+			content.WriteString(summaryLabelStyle.Render(" production lines"))
+		}
+
+		if hasComplexity(m.stats) {
+			content.WriteString("\n")
+			content.WriteString(summaryLabelStyle.Render("Complexity:"))
+			content.WriteString("  ")
+			content.WriteString(summaryValueStyle.Render(fmt.Sprintf("%d", m.stats.TotalComplexity)))
+		}
 	}
 
 	return summaryBoxStyle.Render(content.String())
 }
 
+// renderComplexityDelta renders a complexity change, where growth is the bad direction
+func renderComplexityDelta(delta int) string {
+	switch {
+	case delta > 0:
+		return summaryNegativeStyle.Render(fmt.Sprintf("▲ +%d", delta))
+	case delta < 0:
+		return summaryPositiveStyle.Render(fmt.Sprintf("▼ %d", delta))
+	default:
+		return summaryNeutralStyle.Render("● 0")
+	}
+}
+
 // renderFooter renders the footer with keybindings
 func (m Model) renderFooter(isGitRepo bool) string {
 	var footer strings.Builder
@@ -317,6 +849,16 @@ func (m Model) renderFooter(isGitRepo bool) string {
 		)
 	}
 
+	if len(m.stats.Groups) > 0 {
+		keybindings = append(keybindings, keybindingKeyStyle.Render("v")+" "+keybindingDescStyle.Render("groups/files"))
+	}
+
+	if hasSymbols(m.stats) {
+		keybindings = append(keybindings, keybindingKeyStyle.Render("s")+" "+keybindingDescStyle.Render("symbols"))
+	} else if hasDetails(m.stats) {
+		keybindings = append(keybindings, keybindingKeyStyle.Render("s")+" "+keybindingDescStyle.Render("uncovered lines"))
+	}
+
 	keybindings = append(keybindings, keybindingKeyStyle.Render("q")+" "+keybindingDescStyle.Render("quit"))
 
 	footer.WriteString(strings.Join(keybindings, separatorStyle.Render("  •  ")))
@@ -372,6 +914,26 @@ func (m *Model) sortFiles() {
 
 	sortFunc(m.stats.ChangedFiles)
 	sortFunc(m.stats.UnchangedFiles)
+
+	sort.Slice(m.stats.Groups, func(i, j int) bool {
+		groups := m.stats.Groups
+		var less bool
+		switch m.sortMode {
+		case model.SortByLines:
+			less = groups[i].Lines < groups[j].Lines
+		case model.SortByAdditions:
+			less = groups[i].Additions < groups[j].Additions
+		case model.SortByDeletions:
+			less = groups[i].Deletions < groups[j].Deletions
+		default:
+			less = groups[i].Name < groups[j].Name
+		}
+
+		if m.sortReverse {
+			return !less
+		}
+		return less
+	})
 }
 
 // Run starts the TUI application
@@ -416,7 +978,12 @@ func (m Model) renderStatic() string {
 	b.WriteString(headerStyle.Render("✨ diffloc — Diff Line Counter"))
 	isGitRepo := m.stats.TotalAdditions > 0 || m.stats.TotalDeletions > 0 || m.stats.ChangedCount > 0
 
-	if isGitRepo {
+	if m.showGroups {
+		groupsBadge := badgeStyle.Render(fmt.Sprintf("%d", len(m.stats.Groups)))
+		b.WriteString(sectionHeaderStyle.Render(groupsBadge + " Groups by " + m.stats.GroupBy))
+		b.WriteString("\n")
+		b.WriteString(m.renderGroupTable(m.stats.Groups, isGitRepo))
+	} else if isGitRepo {
 		if len(m.stats.UnchangedFiles) > 0 {
 			unchangedBadge := badgeStyle.Render(fmt.Sprintf("%d", len(m.stats.UnchangedFiles)))
 			b.WriteString(sectionHeaderStyle.Render(unchangedBadge + " Unchanged Files"))
@@ -440,6 +1007,12 @@ func (m Model) renderStatic() string {
 		}
 	}
 
+	if isGitRepo {
+		b.WriteString(m.renderOwnersPanel())
+	}
+
+	b.WriteString(m.renderViolationsPanel())
+
 	b.WriteString(m.renderSummary(isGitRepo))
 	b.WriteString("\n")
 